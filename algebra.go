@@ -0,0 +1,120 @@
+package avltree
+
+// Resolver is a function type that chooses a value for a key that is present in both trees.
+// See Union for details.
+type Resolver[KeyT any, ValueT any] func(key KeyT, a ValueT, b ValueT) ValueT
+
+func unionNodes[KeyT any, ValueT any](a *node[KeyT, ValueT], ha int, b *node[KeyT, ValueT], hb int, cmp Comparator[KeyT], resolve Resolver[KeyT, ValueT], owner *ownerToken) (*node[KeyT, ValueT], int) {
+	if a == nil {
+		return b, hb
+	}
+	if b == nil {
+		return a, ha
+	}
+	al, hal := a.links[0], a.childHeight(ha, 0)
+	ar, har := a.links[1], a.childHeight(ha, 1)
+	bl, hbl, bm, br, hbr := splitNodes(b, hb, a.key, cmp, owner)
+	l, hl := unionNodes(al, hal, bl, hbl, cmp, resolve, owner)
+	r, hr := unionNodes(ar, har, br, hbr, cmp, resolve, owner)
+	if bm != nil && resolve != nil {
+		a = ownNode(&a, owner)
+		a.value = resolve(a.key, a.value, bm.value)
+	}
+	return joinNodes(l, hl, a, r, hr, owner)
+}
+
+func intersectionNodes[KeyT any, ValueT any](a *node[KeyT, ValueT], ha int, b *node[KeyT, ValueT], hb int, cmp Comparator[KeyT], owner *ownerToken) (*node[KeyT, ValueT], int) {
+	if a == nil || b == nil {
+		return nil, 0
+	}
+	al, hal := a.links[0], a.childHeight(ha, 0)
+	ar, har := a.links[1], a.childHeight(ha, 1)
+	bl, hbl, bm, br, hbr := splitNodes(b, hb, a.key, cmp, owner)
+	l, hl := intersectionNodes(al, hal, bl, hbl, cmp, owner)
+	r, hr := intersectionNodes(ar, har, br, hbr, cmp, owner)
+	if bm != nil {
+		return joinNodes(l, hl, a, r, hr, owner)
+	}
+	return joinNodes2(l, hl, r, hr, owner)
+}
+
+func differenceNodes[KeyT any, ValueT any](a *node[KeyT, ValueT], ha int, b *node[KeyT, ValueT], hb int, cmp Comparator[KeyT], owner *ownerToken) (*node[KeyT, ValueT], int) {
+	if a == nil || b == nil {
+		return a, ha
+	}
+	bl, hbl := b.links[0], b.childHeight(hb, 0)
+	br, hbr := b.links[1], b.childHeight(hb, 1)
+	al, hal, _, ar, har := splitNodes(a, ha, b.key, cmp, owner)
+	l, hl := differenceNodes(al, hal, bl, hbl, cmp, owner)
+	r, hr := differenceNodes(ar, har, br, hbr, cmp, owner)
+	return joinNodes2(l, hl, r, hr, owner)
+}
+
+func symmetricDifferenceNodes[KeyT any, ValueT any](a *node[KeyT, ValueT], ha int, b *node[KeyT, ValueT], hb int, cmp Comparator[KeyT], owner *ownerToken) (*node[KeyT, ValueT], int) {
+	if a == nil {
+		return b, hb
+	}
+	if b == nil {
+		return a, ha
+	}
+	al, hal := a.links[0], a.childHeight(ha, 0)
+	ar, har := a.links[1], a.childHeight(ha, 1)
+	bl, hbl, bm, br, hbr := splitNodes(b, hb, a.key, cmp, owner)
+	l, hl := symmetricDifferenceNodes(al, hal, bl, hbl, cmp, owner)
+	r, hr := symmetricDifferenceNodes(ar, har, br, hbr, cmp, owner)
+	if bm != nil {
+		return joinNodes2(l, hl, r, hr, owner)
+	}
+	return joinNodes(l, hl, a, r, hr, owner)
+}
+
+type nodesCombiner[KeyT any, ValueT any] func(a *node[KeyT, ValueT], ha int, b *node[KeyT, ValueT], hb int, cmp Comparator[KeyT], owner *ownerToken) (*node[KeyT, ValueT], int)
+
+// combineTrees builds a new tree from a and b. They are only read, changed nodes are copied and all others are shared.
+// The result has its own owner token, so it copies a shared node before its modification.
+func combineTrees[KeyT any, ValueT any](a, b *AVLTree[KeyT, ValueT], f nodesCombiner[KeyT, ValueT]) *AVLTree[KeyT, ValueT] {
+	result := NewAVLTree[KeyT, ValueT](a.compare)
+	result.owner = new(ownerToken)
+	result.root, _ = f(a.root, avlHeight(a.root), b.root, avlHeight(b.root), a.compare, result.owner)
+	result.count = result.root.getSize()
+	return result
+}
+
+// Union returns a new tree with all elements from a and b trees.
+// When a key is present in both trees 'resolve' chooses a value for it.
+// When resolve is nil a value from the tree a is used.
+// Both trees should use the same Comparator. They are only read, so concurrent readers of them are safe.
+// The result shares unchanged subtrees with a and b. Its own modifications never affect them,
+// but a and b must not be modified while the result is in use, including writes via value pointers
+// obtained before or after the call. Pass a.Snapshot() instead of a tree that is modified later.
+// Complexity is O(m*log(n/m+1)) where m is the smaller tree size, it holds for snapshotted trees too.
+func Union[KeyT any, ValueT any](a, b *AVLTree[KeyT, ValueT], resolve Resolver[KeyT, ValueT]) *AVLTree[KeyT, ValueT] {
+	return combineTrees(a, b, func(a *node[KeyT, ValueT], ha int, b *node[KeyT, ValueT], hb int, cmp Comparator[KeyT], owner *ownerToken) (*node[KeyT, ValueT], int) {
+		return unionNodes(a, ha, b, hb, cmp, resolve, owner)
+	})
+}
+
+// Intersection returns a new tree with elements these keys are present in both a and b trees.
+// Values are taken from the tree a.
+// Both trees should use the same Comparator. They are only read and share subtrees with the result like in Union,
+// so they must not be modified while the result is in use.
+// Complexity is O(m*log(n/m+1)) where m is the smaller tree size, it holds for snapshotted trees too.
+func Intersection[KeyT any, ValueT any](a, b *AVLTree[KeyT, ValueT]) *AVLTree[KeyT, ValueT] {
+	return combineTrees(a, b, intersectionNodes[KeyT, ValueT])
+}
+
+// Difference returns a new tree with elements from the tree a these keys aren't present in the tree b.
+// Both trees should use the same Comparator. They are only read and share subtrees with the result like in Union,
+// so they must not be modified while the result is in use.
+// Complexity is O(m*log(n/m+1)) where m is the smaller tree size, it holds for snapshotted trees too.
+func Difference[KeyT any, ValueT any](a, b *AVLTree[KeyT, ValueT]) *AVLTree[KeyT, ValueT] {
+	return combineTrees(a, b, differenceNodes[KeyT, ValueT])
+}
+
+// SymmetricDifference returns a new tree with elements these keys are present only in one of a and b trees.
+// Both trees should use the same Comparator. They are only read and share subtrees with the result like in Union,
+// so they must not be modified while the result is in use.
+// Complexity is O(m*log(n/m+1)) where m is the smaller tree size, it holds for snapshotted trees too.
+func SymmetricDifference[KeyT any, ValueT any](a, b *AVLTree[KeyT, ValueT]) *AVLTree[KeyT, ValueT] {
+	return combineTrees(a, b, symmetricDifferenceNodes[KeyT, ValueT])
+}
@@ -0,0 +1,212 @@
+package avltree
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTree(rnd *rand.Rand, count int, maxKey int) (*AVLTree[int, int], map[int]int) {
+	tree := NewAVLTreeOrderedKey[int, int]()
+	model := map[int]int{}
+	for i := 0; i < count; i++ {
+		key := rnd.Intn(maxKey)
+		if tree.Insert(key, key*10) == nil {
+			model[key] = key * 10
+		}
+	}
+	return tree, model
+}
+
+func requireTreeContent(require *require.Assertions, tree *AVLTree[int, int], model map[int]int) {
+	requireValidTree(require, tree)
+	require.Equal(uint(len(model)), tree.Size())
+	for k, v := range model {
+		value := tree.Find(k)
+		require.NotNil(value)
+		require.Equal(v, *value)
+	}
+}
+
+func TestAlgebra(t *testing.T) {
+	require := require.New(t)
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		a, ma := createRandomTree(rnd, rnd.Intn(100), 150)
+		b, mb := createRandomTree(rnd, rnd.Intn(100), 150)
+		expected := map[int]int{}
+		for k, v := range ma {
+			expected[k] = v
+		}
+		for k, v := range mb {
+			if _, ok := expected[k]; ok {
+				expected[k] = -1
+			} else {
+				expected[k] = v
+			}
+		}
+		union := Union(a, b, func(k int, va int, vb int) int {
+			require.Equal(ma[k], va)
+			require.Equal(mb[k], vb)
+			return -1
+		})
+		requireTreeContent(require, union, expected)
+		requireTreeContent(require, a, ma)
+		requireTreeContent(require, b, mb)
+
+		a, ma = createRandomTree(rnd, rnd.Intn(100), 150)
+		b, mb = createRandomTree(rnd, rnd.Intn(100), 150)
+		expected = map[int]int{}
+		for k, v := range ma {
+			if _, ok := mb[k]; ok {
+				expected[k] = v
+			}
+		}
+		requireTreeContent(require, Intersection(a, b), expected)
+		requireTreeContent(require, a, ma)
+		requireTreeContent(require, b, mb)
+
+		a, ma = createRandomTree(rnd, rnd.Intn(100), 150)
+		b, mb = createRandomTree(rnd, rnd.Intn(100), 150)
+		expected = map[int]int{}
+		for k, v := range ma {
+			if _, ok := mb[k]; !ok {
+				expected[k] = v
+			}
+		}
+		requireTreeContent(require, Difference(a, b), expected)
+		requireTreeContent(require, a, ma)
+		requireTreeContent(require, b, mb)
+
+		a, ma = createRandomTree(rnd, rnd.Intn(100), 150)
+		b, mb = createRandomTree(rnd, rnd.Intn(100), 150)
+		expected = map[int]int{}
+		for k, v := range ma {
+			if _, ok := mb[k]; !ok {
+				expected[k] = v
+			}
+		}
+		for k, v := range mb {
+			if _, ok := ma[k]; !ok {
+				expected[k] = v
+			}
+		}
+		requireTreeContent(require, SymmetricDifference(a, b), expected)
+		requireTreeContent(require, a, ma)
+		requireTreeContent(require, b, mb)
+	}
+}
+
+func TestUnionNilResolver(t *testing.T) {
+	require := require.New(t)
+
+	a := createTestTree(0, 10, 1)
+	b := NewAVLTreeOrderedKey[int, int]()
+	for i := 5; i <= 15; i++ {
+		b.Insert(i, -i)
+	}
+	union := Union(a, b, nil)
+	require.Equal(uint(16), union.Size())
+	require.Equal(5, *union.Find(5))
+	require.Equal(-15, *union.Find(15))
+}
+
+func TestAlgebraSharing(t *testing.T) {
+	require := require.New(t)
+
+	a := createTestTree(0, 999, 1)
+	b := createTestTree(500, 1499, 1)
+	// a and b are modified later, so the results are built from their snapshots
+	sa := a.Snapshot()
+	sb := b.Snapshot()
+	union := Union(sa, sb, func(key int, va int, vb int) int {
+		return -key
+	})
+	difference := Difference(sa, sb)
+	require.Equal(uint(1500), union.Size())
+	require.Equal(uint(500), difference.Size())
+
+	// All trees share nodes, so modifications of one of them aren't visible via the others
+	*union.Find(10) = -10
+	require.Nil(union.Erase(20))
+	require.Nil(a.Erase(30))
+	*a.Find(40) = -40
+	*b.Find(600) = 0
+	require.Nil(difference.Insert(2000, 2000))
+
+	require.Equal(-600, *union.Find(600))
+	require.Equal(40, *union.Find(40))
+	require.True(union.Contains(30))
+	require.Equal(10, *a.Find(10))
+	require.True(a.Contains(20))
+	require.Equal(40, *difference.Find(40))
+	require.Equal(600, *sb.Find(600))
+	require.Equal(uint(1000), sa.Size())
+	for _, tree := range []*AVLTree[int, int]{a, b, sa, sb, union, difference} {
+		require.Nil(tree.Validate())
+	}
+
+	// Union(a, a) doesn't consume its argument
+	self := Union(b, b, nil)
+	require.True(Equal(self, b, intEq))
+}
+
+func TestAlgebraConcurrentReaders(t *testing.T) {
+	require := require.New(t)
+
+	a := createTestTree(0, 999, 1)
+	b := createTestTree(500, 1499, 1)
+	var wg sync.WaitGroup
+	results := make([]*AVLTree[int, int], 8)
+	sums := make([]int, 8)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			// Combining functions only read their arguments, so they run concurrently with readers and each other
+			if g%2 == 0 {
+				results[g] = Union(a, b, nil)
+			} else {
+				results[g] = Difference(b, a)
+			}
+			a.Enumerate(ASCENDING, func(key int, value int) bool {
+				sums[g] += value
+				return true
+			})
+			for i := 500; i < 1500; i += 7 {
+				if b.Contains(i) {
+					sums[g] += i
+				}
+			}
+			// Every result copies shared nodes before their modification
+			results[g].Erase(700)
+			results[g].Insert(-g-1, g)
+		}(g)
+	}
+	wg.Wait()
+
+	ma, mb := map[int]int{}, map[int]int{}
+	for i := 0; i < 1500; i++ {
+		if i < 1000 {
+			ma[i] = i
+		}
+		if i >= 500 {
+			mb[i] = i
+		}
+	}
+	requireTreeContent(require, a, ma)
+	requireTreeContent(require, b, mb)
+	for g, result := range results {
+		require.Equal(sums[0], sums[g])
+		require.Nil(result.Validate())
+		require.False(result.Contains(700))
+		if g%2 == 0 {
+			require.Equal(uint(1500), result.Size())
+		} else {
+			require.Equal(uint(501), result.Size())
+		}
+	}
+}
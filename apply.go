@@ -0,0 +1,111 @@
+package avltree
+
+import (
+	"errors"
+	"fmt"
+)
+
+// OpKind is a kind of a modification applied by Apply
+type OpKind int
+
+const (
+	// OpInsert inserts a new element. It fails when the key is already present.
+	OpInsert OpKind = iota
+	// OpAssign inserts a new element or replaces a value of the existing one.
+	OpAssign
+	// OpErase removes an element. It fails when the key isn't present.
+	OpErase
+)
+
+// Precondition is a requirement for a key state that is checked before an operation
+type Precondition int
+
+const (
+	// NoPrecondition means the key state isn't checked
+	NoPrecondition Precondition = iota
+	// MustExist requires the key to be present
+	MustExist
+	// MustNotExist requires the key to be absent
+	MustNotExist
+)
+
+// Op is a single modification for Apply.
+// Value is used by OpInsert and OpAssign only.
+// When Expect isn't nil the key must be present and Expect must return true for its current value.
+type Op[KeyT any, ValueT any] struct {
+	Kind         OpKind
+	Key          KeyT
+	Value        ValueT
+	Precondition Precondition
+	Expect       func(current ValueT) bool
+}
+
+// ExpectValue returns an Op.Expect function that requires the current value to be equal to the expected one.
+func ExpectValue[ValueT comparable](expected ValueT) func(current ValueT) bool {
+	return func(current ValueT) bool {
+		return current == expected
+	}
+}
+
+// undoRecord keeps a key state before an applied operation
+type undoRecord[KeyT any, ValueT any] struct {
+	key     KeyT
+	existed bool
+	value   ValueT
+}
+
+func (t *AVLTree[KeyT, ValueT]) applyOp(op *Op[KeyT, ValueT]) (undoRecord[KeyT, ValueT], error) {
+	record := undoRecord[KeyT, ValueT]{key: op.Key}
+	if n := t.lookupNode(op.Key); n != nil {
+		record.existed, record.value = true, n.value
+	}
+
+	switch {
+	case op.Precondition == MustExist && !record.existed:
+		return record, &KeyError[KeyT]{Key: op.Key, Err: ErrKeyNotFound}
+	case op.Precondition == MustNotExist && record.existed:
+		return record, &KeyError[KeyT]{Key: op.Key, Err: ErrKeyExists}
+	case op.Expect != nil && (!record.existed || !op.Expect(record.value)):
+		return record, &KeyError[KeyT]{Key: op.Key, Err: ErrUnexpectedValue}
+	}
+
+	switch op.Kind {
+	case OpInsert:
+		return record, t.Insert(op.Key, op.Value)
+	case OpAssign:
+		t.InsertOrAssign(op.Key, op.Value)
+		return record, nil
+	case OpErase:
+		return record, t.Erase(op.Key)
+	}
+	return record, errors.New("AVLTree: unknown operation kind")
+}
+
+// Apply applies all operations in the given order. Every operation observes results of the previous ones.
+// When an operation or its precondition fails all applied operations are rolled back,
+// so the tree content stays unchanged, and an error that wraps the failure reason is returned.
+// Reasons are ErrKeyExists, ErrKeyNotFound and ErrUnexpectedValue, all except a failed OpInsert are KeyError.
+// Pointers obtained before the call can be invalidated by a rollback.
+func (t *AVLTree[KeyT, ValueT]) Apply(ops []Op[KeyT, ValueT]) error {
+	undo := make([]undoRecord[KeyT, ValueT], 0, len(ops))
+	for i := range ops {
+		record, err := t.applyOp(&ops[i])
+		if err != nil {
+			t.rollback(undo)
+			return fmt.Errorf("AVLTree: operation %d failed: %w", i, err)
+		}
+		undo = append(undo, record)
+	}
+	return nil
+}
+
+// rollback restores key states in the reverse order
+func (t *AVLTree[KeyT, ValueT]) rollback(undo []undoRecord[KeyT, ValueT]) {
+	for i := len(undo) - 1; i >= 0; i-- {
+		if undo[i].existed {
+			t.InsertOrAssign(undo[i].key, undo[i].value)
+		} else {
+			t.Remove(undo[i].key)
+		}
+	}
+}
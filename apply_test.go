@@ -0,0 +1,138 @@
+package avltree
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	require := require.New(t)
+
+	tree := createTestTree(0, 9, 1)
+	err := tree.Apply([]Op[int, int]{
+		{Kind: OpInsert, Key: 10, Value: 100},
+		{Kind: OpAssign, Key: 5, Value: 50, Precondition: MustExist, Expect: ExpectValue(5)},
+		{Kind: OpAssign, Key: 11, Value: 110, Precondition: MustNotExist},
+		{Kind: OpErase, Key: 0},
+		{Kind: OpInsert, Key: 0, Value: -1},
+		{Kind: OpErase, Key: 11, Expect: ExpectValue(110)},
+	})
+	require.Nil(err)
+	require.Nil(tree.Validate())
+	require.Equal(uint(11), tree.Size())
+	require.Equal(100, *tree.Find(10))
+	require.Equal(50, *tree.Find(5))
+	require.Equal(-1, *tree.Find(0))
+	require.False(tree.Contains(11))
+
+	require.Nil(tree.Apply(nil))
+	require.Equal(uint(11), tree.Size())
+}
+
+func TestApplyRollback(t *testing.T) {
+	require := require.New(t)
+
+	model := map[int]int{}
+	for i := 0; i <= 9; i++ {
+		model[i] = i
+	}
+	prefix := []Op[int, int]{
+		{Kind: OpInsert, Key: 20, Value: 20},
+		{Kind: OpAssign, Key: 1, Value: -1},
+		{Kind: OpAssign, Key: 21, Value: 21},
+		{Kind: OpErase, Key: 2},
+		{Kind: OpInsert, Key: 2, Value: -2},
+		{Kind: OpErase, Key: 3},
+	}
+
+	failures := []struct {
+		op     Op[int, int]
+		reason error
+	}{
+		{Op[int, int]{Kind: OpInsert, Key: 20}, ErrKeyExists},
+		{Op[int, int]{Kind: OpErase, Key: 3}, ErrKeyNotFound},
+		{Op[int, int]{Kind: OpAssign, Key: 30, Precondition: MustExist}, ErrKeyNotFound},
+		{Op[int, int]{Kind: OpAssign, Key: 4, Precondition: MustNotExist}, ErrKeyExists},
+		{Op[int, int]{Kind: OpErase, Key: 1, Expect: ExpectValue(1)}, ErrUnexpectedValue},
+		{Op[int, int]{Kind: OpAssign, Key: 3, Expect: ExpectValue(3)}, ErrUnexpectedValue},
+	}
+	for _, failure := range failures {
+		tree := createTestTree(0, 9, 1)
+		err := tree.Apply(append(append([]Op[int, int]{}, prefix...), failure.op))
+		require.ErrorIs(err, failure.reason)
+		requireTreeContent(require, tree, model)
+	}
+
+	tree := createTestTree(0, 9, 1)
+	err := tree.Apply([]Op[int, int]{{Kind: OpErase, Key: 0}, {Kind: OpErase, Key: 42}})
+	var keyErr *KeyError[int]
+	require.True(errors.As(err, &keyErr))
+	require.Equal(42, keyErr.Key)
+	requireTreeContent(require, tree, model)
+
+	err = tree.Apply([]Op[int, int]{{Kind: OpErase, Key: 0}, {Kind: OpKind(42), Key: 1}})
+	require.NotNil(err)
+	requireTreeContent(require, tree, model)
+}
+
+func TestApplyRandom(t *testing.T) {
+	require := require.New(t)
+
+	rnd := rand.New(rand.NewSource(1))
+	tree, model := createRandomTree(rnd, 200, 400)
+	for i := 0; i < 500; i++ {
+		ops := make([]Op[int, int], rnd.Intn(20))
+		for j := range ops {
+			ops[j] = Op[int, int]{Kind: OpKind(rnd.Intn(3)), Key: rnd.Intn(400), Value: rnd.Int()}
+		}
+
+		expected := map[int]int{}
+		for k, v := range model {
+			expected[k] = v
+		}
+		ok := true
+		for _, op := range ops {
+			_, exists := expected[op.Key]
+			switch {
+			case op.Kind == OpInsert && exists, op.Kind == OpErase && !exists:
+				ok = false
+			case op.Kind == OpErase:
+				delete(expected, op.Key)
+			default:
+				expected[op.Key] = op.Value
+			}
+			if !ok {
+				break
+			}
+		}
+
+		err := tree.Apply(ops)
+		require.Equal(ok, err == nil)
+		if ok {
+			model = expected
+		}
+		requireTreeContent(require, tree, model)
+	}
+}
+
+func TestApplySnapshot(t *testing.T) {
+	require := require.New(t)
+
+	tree := createTestTree(0, 99, 1)
+	snapshot := tree.Snapshot()
+	require.NotNil(tree.Apply([]Op[int, int]{
+		{Kind: OpErase, Key: 10},
+		{Kind: OpAssign, Key: 20, Value: -20},
+		{Kind: OpInsert, Key: 30},
+	}))
+	require.Nil(tree.Apply([]Op[int, int]{{Kind: OpErase, Key: 40}}))
+	require.Equal(uint(99), tree.Size())
+	require.Equal(20, *tree.Find(20))
+	require.Nil(tree.Validate())
+	require.Equal(uint(100), snapshot.Size())
+	require.True(snapshot.Contains(40))
+	require.Nil(snapshot.Validate())
+}
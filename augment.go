@@ -0,0 +1,211 @@
+package avltree
+
+import "golang.org/x/exp/constraints"
+
+// Augmenter defines a monoid over elements of AugmentedAVLTree.
+// Every tree node keeps an aggregate of its subtree, so aggregates of any key range are computed in logarithmic time.
+// For example a sum of values, a minimum, a count or any other associative operation.
+type Augmenter[KeyT any, ValueT any, AggT any] interface {
+	// Identity returns the neutral element: Combine(Identity(), a) == Combine(a, Identity()) == a
+	Identity() AggT
+	// Lift returns an aggregate of a single element
+	Lift(key KeyT, value ValueT) AggT
+	// Combine joins aggregates of two adjacent key ranges. 'left' range keys are lesser than 'right' ones.
+	// It must be associative but isn't required to be commutative.
+	Combine(left AggT, right AggT) AggT
+}
+
+// augmentation is shared by an AugmentedAVLTree and all its nodes.
+type augmentation[KeyT any, ValueT any, AggT any] struct {
+	augmenter Augmenter[KeyT, ValueT, AggT]
+}
+
+// augmentedValue is a value stored in AugmentedAVLTree nodes.
+// agg is an aggregate of the node subtree.
+type augmentedValue[KeyT any, ValueT any, AggT any] struct {
+	value ValueT
+	agg   AggT
+	owner *augmentation[KeyT, ValueT, AggT]
+}
+
+func (v *augmentedValue[KeyT, ValueT, AggT]) augment(n *node[KeyT, augmentedValue[KeyT, ValueT, AggT]]) {
+	a := v.owner.augmenter
+	v.agg = a.Lift(n.key, v.value)
+	if left := n.links[0]; left != nil {
+		v.agg = a.Combine(left.value.agg, v.agg)
+	}
+	if right := n.links[1]; right != nil {
+		v.agg = a.Combine(v.agg, right.value.agg)
+	}
+}
+
+// AugmentedAVLTree is AVLTree that keeps an aggregate defined by Augmenter in every node.
+// Aggregates are recomputed by rotations and along insertion and erasing paths,
+// so Aggregate answers for any key range in logarithmic time.
+// As usual AugmentedAVLTree instance creation is allowed via `NewAugmentedAVLTreeOrderedKey` or `NewAugmentedAVLTree`
+type AugmentedAVLTree[KeyT any, ValueT any, AggT any] struct {
+	tree         *AVLTree[KeyT, augmentedValue[KeyT, ValueT, AggT]]
+	augmentation *augmentation[KeyT, ValueT, AggT]
+}
+
+// NewAugmentedAVLTree creates a new AugmentedAVLTree instance with the given Comparator and Augmenter
+func NewAugmentedAVLTree[KeyT any, ValueT any, AggT any](c Comparator[KeyT], a Augmenter[KeyT, ValueT, AggT]) *AugmentedAVLTree[KeyT, ValueT, AggT] {
+	return &AugmentedAVLTree[KeyT, ValueT, AggT]{
+		tree:         NewAVLTree[KeyT, augmentedValue[KeyT, ValueT, AggT]](c),
+		augmentation: &augmentation[KeyT, ValueT, AggT]{augmenter: a},
+	}
+}
+
+// NewAugmentedAVLTreeOrderedKey creates a new AugmentedAVLTree instance where Key type is constraints.Ordered.
+// This is actually the same as NewAugmentedAVLTree but Comparator will be defined automaticaly inside the call.
+func NewAugmentedAVLTreeOrderedKey[KeyT constraints.Ordered, ValueT any, AggT any](a Augmenter[KeyT, ValueT, AggT]) *AugmentedAVLTree[KeyT, ValueT, AggT] {
+	return NewAugmentedAVLTree[KeyT, ValueT, AggT](orderedComparator[KeyT], a)
+}
+
+// Size returns the number of elements
+func (t *AugmentedAVLTree[KeyT, ValueT, AggT]) Size() uint {
+	return t.tree.Size()
+}
+
+// Empty checks whether the container is empty
+func (t *AugmentedAVLTree[KeyT, ValueT, AggT]) Empty() bool {
+	return t.tree.Empty()
+}
+
+// Contains checks if the container contains element with the specific key
+func (t *AugmentedAVLTree[KeyT, ValueT, AggT]) Contains(key KeyT) bool {
+	return t.tree.Contains(key)
+}
+
+// Find finds element with specific key
+// Returns a copy of the associated with the key value and true or zero value and false.
+// Use Modify or Assign for a value modification since aggregates depend on it.
+func (t *AugmentedAVLTree[KeyT, ValueT, AggT]) Find(key KeyT) (ValueT, bool) {
+	n := t.tree.lookupNode(key)
+	if n == nil {
+		var zero ValueT
+		return zero, false
+	}
+	return n.value.value, true
+}
+
+// Modify calls 'f' with a pointer on the value associated with the given key.
+// Aggregates these depend on the value are recomputed right after the call.
+// The pointer must not be used after 'f' returns. 'f' must not modify the tree.
+// Returns false when the key isn't present.
+// Complexity is logarithmic.
+func (t *AugmentedAVLTree[KeyT, ValueT, AggT]) Modify(key KeyT, f func(value *ValueT)) bool {
+	n := t.tree.own(t.tree.lookupNode(key))
+	if n == nil {
+		return false
+	}
+	f(&n.value.value)
+	augmentPath(t.tree.root, key, t.tree.compare)
+	return true
+}
+
+// Assign replaces the value associated with the given key.
+// Returns false when the key isn't present.
+// Complexity is logarithmic.
+func (t *AugmentedAVLTree[KeyT, ValueT, AggT]) Assign(key KeyT, value ValueT) bool {
+	return t.Modify(key, func(v *ValueT) {
+		*v = value
+	})
+}
+
+// Insert inserts an element with the given key and value.
+// It the given key is already present returns an error.
+func (t *AugmentedAVLTree[KeyT, ValueT, AggT]) Insert(key KeyT, value ValueT) error {
+	return t.tree.Insert(key, augmentedValue[KeyT, ValueT, AggT]{value: value, owner: t.augmentation})
+}
+
+// Erase removes an element by the given key
+// Can return an error when such Key wasn't present.
+func (t *AugmentedAVLTree[KeyT, ValueT, AggT]) Erase(key KeyT) error {
+	return t.tree.Erase(key)
+}
+
+// Clear removes all tree content
+func (t *AugmentedAVLTree[KeyT, ValueT, AggT]) Clear() {
+	t.tree.Clear()
+}
+
+// Enumerate calls 'Enumerator' for every Tree's element.
+// See AVLTree.Enumerate for the details.
+func (t *AugmentedAVLTree[KeyT, ValueT, AggT]) Enumerate(order EnumerationOrder, f Enumerator[KeyT, ValueT]) {
+	t.tree.Enumerate(order, func(key KeyT, value augmentedValue[KeyT, ValueT, AggT]) bool {
+		return f(key, value.value)
+	})
+}
+
+// EnumerateDiapason works like Enumerate but has two additional args - left and right
+// See AVLTree.EnumerateDiapason for the details.
+func (t *AugmentedAVLTree[KeyT, ValueT, AggT]) EnumerateDiapason(left, right *KeyT, order EnumerationOrder, f Enumerator[KeyT, ValueT]) error {
+	return t.tree.EnumerateDiapason(left, right, order, func(key KeyT, value augmentedValue[KeyT, ValueT, AggT]) bool {
+		return f(key, value.value)
+	})
+}
+
+func (t *AugmentedAVLTree[KeyT, ValueT, AggT]) aggregate(n *node[KeyT, augmentedValue[KeyT, ValueT, AggT]]) AggT {
+	if n == nil {
+		return t.augmentation.augmenter.Identity()
+	}
+	return n.value.agg
+}
+
+// aggregateFrom returns an aggregate of n subtree keys these are greater or equal to 'left'.
+func (t *AugmentedAVLTree[KeyT, ValueT, AggT]) aggregateFrom(n *node[KeyT, augmentedValue[KeyT, ValueT, AggT]], left *KeyT) AggT {
+	a := t.augmentation.augmenter
+	result := a.Identity()
+	for n != nil {
+		if left == nil {
+			return a.Combine(n.value.agg, result)
+		}
+		if t.tree.compare(n.key, *left) < 0 {
+			n = n.links[1]
+			continue
+		}
+		result = a.Combine(a.Combine(a.Lift(n.key, n.value.value), t.aggregate(n.links[1])), result)
+		n = n.links[0]
+	}
+	return result
+}
+
+// aggregateTo returns an aggregate of n subtree keys these are lesser or equal to 'right'.
+func (t *AugmentedAVLTree[KeyT, ValueT, AggT]) aggregateTo(n *node[KeyT, augmentedValue[KeyT, ValueT, AggT]], right *KeyT) AggT {
+	a := t.augmentation.augmenter
+	result := a.Identity()
+	for n != nil {
+		if right == nil {
+			return a.Combine(result, n.value.agg)
+		}
+		if t.tree.compare(n.key, *right) > 0 {
+			n = n.links[0]
+			continue
+		}
+		result = a.Combine(result, a.Combine(t.aggregate(n.links[0]), a.Lift(n.key, n.value.value)))
+		n = n.links[1]
+	}
+	return result
+}
+
+// Aggregate returns an aggregate of all elements these keys are in the [left, right] range.
+// A nil border means that the range isn't limited from this side, so Aggregate(nil, nil) is an aggregate of the whole tree.
+// Returns Augmenter.Identity() for an empty range, including the case when left is greater than right.
+// Complexity is logarithmic.
+func (t *AugmentedAVLTree[KeyT, ValueT, AggT]) Aggregate(left, right *KeyT) AggT {
+	n := t.tree.root
+	for n != nil {
+		if left != nil && t.tree.compare(n.key, *left) < 0 {
+			n = n.links[1]
+		} else if right != nil && t.tree.compare(n.key, *right) > 0 {
+			n = n.links[0]
+		} else {
+			// The node is the highest one inside the range, so its subtrees are bounded from one side only
+			a := t.augmentation.augmenter
+			result := a.Combine(t.aggregateFrom(n.links[0], left), a.Lift(n.key, n.value.value))
+			return a.Combine(result, t.aggregateTo(n.links[1], right))
+		}
+	}
+	return t.augmentation.augmenter.Identity()
+}
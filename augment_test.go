@@ -0,0 +1,167 @@
+package avltree
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sumAugmenter struct{}
+
+func (sumAugmenter) Identity() int                   { return 0 }
+func (sumAugmenter) Lift(key int, value int) int     { return value }
+func (sumAugmenter) Combine(left int, right int) int { return left + right }
+
+// concatAugmenter isn't commutative so it checks the combining order
+type concatAugmenter struct{}
+
+func (concatAugmenter) Identity() string                         { return "" }
+func (concatAugmenter) Lift(key int, value string) string        { return value }
+func (concatAugmenter) Combine(left string, right string) string { return left + right }
+
+// checkAggregate returns an aggregate of n subtree. It panics when the stored aggregate is wrong.
+func checkAggregate[AggT comparable](n *node[int, augmentedValue[int, int, AggT]]) AggT {
+	a := n.value.owner.augmenter
+	agg := a.Lift(n.key, n.value.value)
+	if n.links[0] != nil {
+		agg = a.Combine(checkAggregate(n.links[0]), agg)
+	}
+	if n.links[1] != nil {
+		agg = a.Combine(agg, checkAggregate(n.links[1]))
+	}
+	if agg != n.value.agg {
+		panic("Wrong aggregate")
+	}
+	return agg
+}
+
+func TestAugmentedModification(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAugmentedAVLTreeOrderedKey[int, int, int](sumAugmenter{})
+	require.True(tree.Empty())
+	require.Equal(0, tree.Aggregate(nil, nil))
+	for i := 1; i <= 100; i++ {
+		require.Nil(tree.Insert(i, i))
+	}
+	require.Error(tree.Insert(1, 1))
+	require.Equal(uint(100), tree.Size())
+	require.True(tree.Contains(100))
+
+	left, right := 10, 20
+	require.Equal(5050, tree.Aggregate(nil, nil))
+	require.Equal(165, tree.Aggregate(&left, &right))
+	require.Equal(5050-45, tree.Aggregate(&left, nil))
+	require.Equal(210, tree.Aggregate(nil, &right))
+	require.Equal(0, tree.Aggregate(&right, &left))
+
+	require.Nil(tree.Erase(15))
+	require.Error(tree.Erase(15))
+	require.Equal(150, tree.Aggregate(&left, &right))
+	checkAggregate(tree.tree.root)
+
+	require.True(tree.Assign(10, 1000))
+	require.True(tree.Modify(20, func(value *int) {
+		*value *= 100
+	}))
+	require.False(tree.Assign(15, 1500))
+	require.False(tree.Modify(15, func(value *int) {
+		require.Fail("Modify calls f for an absent key")
+	}))
+	value, ok := tree.Find(20)
+	require.True(ok)
+	require.Equal(2000, value)
+	_, ok = tree.Find(15)
+	require.False(ok)
+	require.Equal(150-30+3000, tree.Aggregate(&left, &right))
+	checkAggregate(tree.tree.root)
+
+	sum := 0
+	require.Nil(tree.EnumerateDiapason(&left, &right, ASCENDING, func(key int, value int) bool {
+		sum += value
+		return true
+	}))
+	require.Equal(150-30+3000, sum)
+
+	tree.Clear()
+	require.True(tree.Empty())
+	require.Equal(0, tree.Aggregate(nil, nil))
+}
+
+func TestAugmentedRandom(t *testing.T) {
+	require := require.New(t)
+
+	rnd := rand.New(rand.NewSource(1))
+	tree := NewAugmentedAVLTreeOrderedKey[int, string, string](concatAugmenter{})
+	model := map[int]string{}
+	for i := 0; i < 2000; i++ {
+		key := rnd.Intn(300)
+		switch rnd.Intn(4) {
+		case 0:
+			if _, ok := model[key]; ok {
+				require.Nil(tree.Erase(key))
+				delete(model, key)
+			}
+		case 1:
+			value := fmt.Sprintf("[%d:%d]", key, i)
+			if tree.Assign(key, value) {
+				model[key] = value
+			}
+		default:
+			if _, ok := model[key]; !ok {
+				model[key] = fmt.Sprintf("(%d)", key)
+				require.Nil(tree.Insert(key, model[key]))
+			}
+		}
+
+		left, right := rnd.Intn(300), rnd.Intn(300)
+		expected := ""
+		for k := left; k <= right; k++ {
+			expected += model[k]
+		}
+		require.Equal(expected, tree.Aggregate(&left, &right))
+	}
+
+	all := ""
+	tree.Enumerate(ASCENDING, func(key int, value string) bool {
+		all += value
+		return true
+	})
+	require.Equal(all, tree.Aggregate(nil, nil))
+}
+
+func TestAugmentedRotations(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAugmentedAVLTreeOrderedKey[int, int, int](sumAugmenter{})
+	rnd := rand.New(rand.NewSource(2))
+	for _, key := range rnd.Perm(1000) {
+		require.Nil(tree.Insert(key, key))
+		checkAggregate(tree.tree.root)
+	}
+	for _, key := range rnd.Perm(1000)[:900] {
+		require.Nil(tree.Erase(key))
+		checkAggregate(tree.tree.root)
+	}
+}
+
+func TestAugmentedModifyLarge(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAugmentedAVLTreeOrderedKey[int, int, int](sumAugmenter{})
+	for i := 0; i < 1000; i++ {
+		require.Nil(tree.Insert(i, 1))
+	}
+	require.Equal(1000, tree.Aggregate(nil, nil))
+
+	// Every modification is visible for the next Aggregate call
+	for i := 0; i < 100; i++ {
+		require.True(tree.Assign(i*7, 100))
+		require.Equal(1000+(i+1)*99, tree.Aggregate(nil, nil))
+	}
+	left, right := 0, 69
+	require.Equal(10*100+60, tree.Aggregate(&left, &right))
+	checkAggregate(tree.tree.root)
+}
@@ -8,7 +8,6 @@
 package avltree
 
 import (
-	"errors"
 	"fmt"
 	"io"
 	"math/bits"
@@ -57,12 +56,17 @@ type node[KeyT any, ValueT any] struct {
 	key   KeyT
 	value ValueT
 	links [2]*node[KeyT, ValueT]
+	// Number of nodes in the subtree including the node itself
+	size uint
 
 	// Balance:
 	// -1 - balanced
 	//  1 - right higher
 	//  0 - left higher
 	balance int
+
+	// A tree that is allowed to modify the node. See AVLTree.Snapshot for the details.
+	owner *ownerToken
 }
 
 func getHeight[KeyT any, ValueT any](n *node[KeyT, ValueT]) int {
@@ -72,6 +76,51 @@ func getHeight[KeyT any, ValueT any](n *node[KeyT, ValueT]) int {
 	return max(getHeight(n.links[0]), getHeight(n.links[1])) + 1
 }
 
+func (n *node[KeyT, ValueT]) getSize() uint {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// nodeAugmenter is implemented by internal value types these keep an aggregate of the whole subtree
+// in every node (like IntervalTree does). augment recomputes the aggregate of n from n itself and its children.
+type nodeAugmenter[KeyT any, ValueT any] interface {
+	augment(n *node[KeyT, ValueT])
+}
+
+// isAugmented checks whether ValueT keeps a subtree aggregate
+func isAugmented[KeyT any, ValueT any]() bool {
+	_, ok := any((*ValueT)(nil)).(nodeAugmenter[KeyT, ValueT])
+	return ok
+}
+
+// update recomputes the subtree size and the augmentation of the node from its children.
+func (n *node[KeyT, ValueT]) update() {
+	n.size = n.links[0].getSize() + n.links[1].getSize() + 1
+	if a, ok := any(&n.value).(nodeAugmenter[KeyT, ValueT]); ok {
+		a.augment(n)
+	}
+}
+
+// augmentPath recomputes the augmentation bottom-up along the search path of the key.
+// Search doesn't stop on the equal key but continues via the left link.
+// Rotations update nodes these leave the path, so after insertion (with the inserted key) and
+// erasing (with the key of the node that has been moved into the erased position) it is
+// enough to fix only this path.
+func augmentPath[KeyT any, ValueT any](root *node[KeyT, ValueT], key KeyT, cmp Comparator[KeyT]) {
+	if !isAugmented[KeyT, ValueT]() {
+		return
+	}
+	path := make([]*node[KeyT, ValueT], 0, maxHeight(root.getSize())+1)
+	for n := root; n != nil; n = n.links[n.getDirection(cmp(key, n.key))] {
+		path = append(path, n)
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i].update()
+	}
+}
+
 func (n *node[KeyT, ValueT]) getDirection(cmpResult int) int {
 	// if cmpResult == -1 {
 	// 	return 0
@@ -108,6 +157,8 @@ func rotate2[KeyT any, ValueT any](pathTop **node[KeyT, ValueT], dir int) *node[
 	*pathTop = nodeD
 	nodeD.links[1-dir] = nodeB
 	nodeB.links[dir] = nodeC
+	nodeB.update()
+	nodeD.update()
 
 	return nodeE
 }
@@ -124,6 +175,9 @@ func rotate3[KeyT any, ValueT any](pathTop **node[KeyT, ValueT], dir int) {
 	nodeD.links[dir] = nodeF
 	nodeB.links[dir] = nodeC
 	nodeF.links[1-dir] = nodeE
+	nodeB.update()
+	nodeF.update()
+	nodeD.update()
 }
 
 func avlRotate2[KeyT any, ValueT any](pathTop **node[KeyT, ValueT], dir int) *node[KeyT, ValueT] {
@@ -160,36 +214,73 @@ func avlRotate3[KeyT any, ValueT any](pathTop **node[KeyT, ValueT], dir int, thi
 	}
 }
 
-func avlInsert[KeyT any, ValueT any](root **node[KeyT, ValueT], key KeyT, value ValueT, cmp Comparator[KeyT]) bool {
-	//Stage 1. Find a position in the tree and link a new node
-	// by the way find and remember a node where the tree starts to be unbalanced.
-	pathTop := root // Unbalanced node
-	nodePtr := root // *nodePtr - a new node
-
-	for nodePtr = root; *nodePtr != nil; {
-		n := *nodePtr
+// avlFixup restores balance of the *pathTop node where 'dir' subtree is higher by 2 than the other one.
+func avlFixup[KeyT any, ValueT any](pathTop **node[KeyT, ValueT], dir int) {
+	n := *pathTop
+	second := n.links[dir].balance
+	if second == 1-dir {
+		avlRotate3(pathTop, dir, n.links[dir].links[1-dir].balance)
+	} else if second == -1 {
+		avlRotate2(pathTop, dir)
+		n.balance = dir
+		(*pathTop).balance = 1 - dir
+	} else {
+		avlRotate2(pathTop, dir)
+	}
+}
+
+// avlInsert inserts a new node when the key isn't present.
+// Returns the node with the key and true when it has been inserted.
+// Returns the existing node and false when the key is already present, the node isn't taken for the owner.
+// When 'create' isn't nil it is called before any tree modification and provides the value of a new node.
+// Nothing is inserted and (nil, false) is returned when it returns keep == false.
+// Nodes on the path these don't belong to the owner are copied.
+func avlInsert[KeyT any, ValueT any](root **node[KeyT, ValueT], key KeyT, value ValueT, create func() (ValueT, bool), cmp Comparator[KeyT], owner *ownerToken) (*node[KeyT, ValueT], bool) {
+	//Stage 1. Find a position in the tree without any modification.
+	// Directions are remembered one bit per level, so the path can be passed again without comparisons.
+	// AVL tree height never exceeds 1.45*log2(n+2), so 128 bits are enough for any count.
+	var dirs [2]uint64
+	depth := 0
+	for n := *root; n != nil; depth++ {
 		cmpRes := cmp(key, n.key)
 		if cmpRes == 0 {
-			break
-		}
-		if !n.avlIsBalanced() {
-			pathTop = nodePtr
+			return n, false //already has the key
 		}
 		dir := n.getDirection(cmpRes)
-		nodePtr = &(n.links[dir])
+		dirs[depth>>6] |= uint64(dir) << (depth & 63)
+		n = n.links[dir]
+	}
+	if create != nil {
+		var keep bool
+		if value, keep = create(); !keep {
+			return nil, false
+		}
 	}
 
-	if *nodePtr != nil {
-		return false //already has the key
+	//Stage 2. Pass the path again and link a new node
+	// by the way find and remember a node where the tree starts to be unbalanced.
+	// Subtree sizes are incremented along the path.
+	pathTop := root // Unbalanced node
+	nodePtr := root // *nodePtr - a new node
+	for i := 0; i < depth; i++ {
+		n := ownNode(nodePtr, owner)
+		n.size++
+		if !n.avlIsBalanced() {
+			pathTop = nodePtr
+		}
+		nodePtr = &n.links[(dirs[i>>6]>>(i&63))&1]
 	}
 
-	*nodePtr = &node[KeyT, ValueT]{
+	newNode := &node[KeyT, ValueT]{
 		key:     key,
 		value:   value,
+		size:    1,
 		balance: -1,
+		owner:   owner,
 	}
+	*nodePtr = newNode
 
-	//Stage 2. Rebalance
+	//Stage 3. Rebalance
 	path := *pathTop
 	var first, second, third int
 	if !path.avlIsBalanced() {
@@ -221,7 +312,7 @@ func avlInsert[KeyT any, ValueT any](root **node[KeyT, ValueT], key KeyT, value
 		}
 	}
 
-	//Stage 3. Update balance info in the each node
+	//Stage 4. Update balance info in the each node
 	for path != nil {
 		cmpRes := cmp(key, path.key)
 		if cmpRes == 0 {
@@ -231,30 +322,53 @@ func avlInsert[KeyT any, ValueT any](root **node[KeyT, ValueT], key KeyT, value
 		path.balance = direction
 		path = path.links[direction]
 	}
-	return true
+
+	augmentPath(*root, key, cmp)
+	return newNode, true
 }
 
-func avlErase[KeyT any, ValueT any](root **node[KeyT, ValueT], key KeyT, cmp Comparator[KeyT]) *node[KeyT, ValueT] {
+// nodeLocator compares a searched node with the given one like Comparator does.
+// It allows to erase nodes these keys aren't known before the descent, like the first one.
+type nodeLocator[KeyT any, ValueT any] func(n *node[KeyT, ValueT]) int
+
+func avlErase[KeyT any, ValueT any](root **node[KeyT, ValueT], key KeyT, cmp Comparator[KeyT], owner *ownerToken) *node[KeyT, ValueT] {
+	return avlEraseLocated(root, func(n *node[KeyT, ValueT]) int {
+		return cmp(key, n.key)
+	}, cmp, owner)
+}
+
+// avlEraseLocated removes the node found by 'locate'. Returns the removed node or nil.
+// 'locate' must order nodes consistently with cmp.
+// Nodes on the path and nodes changed by rotations these don't belong to the owner are copied.
+func avlEraseLocated[KeyT any, ValueT any](root **node[KeyT, ValueT], locate nodeLocator[KeyT, ValueT], cmp Comparator[KeyT], owner *ownerToken) *node[KeyT, ValueT] {
 	//Stage 1. lookup for the node that contain a key
+	// Subtree sizes are optimistically decremented along the path.
 	var targetPtr **node[KeyT, ValueT]
 	var dir int
 	pathTop := root // Adjust balance start node
 
 	for nodePtr := root; *nodePtr != nil; {
-		n := *nodePtr
-		cmpRes := cmp(key, n.key)
+		n := ownNode(nodePtr, owner)
+		n.size--
+		cmpRes := locate(n)
 		dir = n.getDirection(cmpRes)
 		if cmpRes == 0 {
 			targetPtr = nodePtr
-		} else if n.links[dir] == nil {
+		}
+		if n.links[dir] == nil {
 			break
-		} else if n.avlIsBalanced() || (n.balance == (1-dir) && n.links[1-dir].avlIsBalanced()) {
+		}
+		if n.avlIsBalanced() || (n.balance == (1-dir) && n.links[1-dir].avlIsBalanced()) {
 			pathTop = nodePtr
 		}
 		nodePtr = &n.links[dir]
 	}
 	if targetPtr == nil {
-		return nil //key not found nothing to remove
+		//key not found nothing to remove. Rollback sizes
+		for n := *root; n != nil; n = n.links[n.getDirection(locate(n))] {
+			n.size++
+		}
+		return nil
 	}
 
 	/*
@@ -268,7 +382,7 @@ func avlErase[KeyT any, ValueT any](root **node[KeyT, ValueT], key KeyT, cmp Com
 	targetn := *targetPtr
 	for {
 		tree := *treep
-		cmpRes := cmp(key, tree.key)
+		cmpRes := locate(tree)
 		bdir := tree.getDirection(cmpRes)
 		if tree.links[bdir] == nil {
 			break
@@ -277,16 +391,12 @@ func avlErase[KeyT any, ValueT any](root **node[KeyT, ValueT], key KeyT, cmp Com
 		} else if tree.balance == bdir {
 			tree.balance = -1
 		} else {
-			second := tree.links[1-bdir].balance
-			if second == bdir {
-				avlRotate3(treep, 1-bdir, tree.links[1-bdir].links[bdir].balance)
-			} else if second == -1 {
-				avlRotate2(treep, 1-bdir)
-				tree.balance = 1 - bdir
-				(*treep).balance = bdir
-			} else {
-				avlRotate2(treep, 1-bdir)
+			// Rotation changes the sibling and its inner child for a double rotation
+			sibling := ownNode(&tree.links[1-bdir], owner)
+			if sibling.balance == bdir {
+				ownNode(&sibling.links[bdir], owner)
 			}
+			avlFixup(treep, 1-bdir)
 			if tree == targetn {
 				targetPtr = &(*treep).links[bdir]
 			}
@@ -307,7 +417,9 @@ func avlErase[KeyT any, ValueT any](root **node[KeyT, ValueT], key KeyT, cmp Com
 	tree.links[0] = targetn.links[0]
 	tree.links[1] = targetn.links[1]
 	tree.balance = targetn.balance
+	tree.size = targetn.size
 
+	augmentPath(*root, tree.key, cmp)
 	return targetn
 }
 
@@ -333,6 +445,22 @@ func (t *AVLTree[KeyT, ValueT]) findEdgeNodeImpl(key KeyT, dir int) *node[KeyT,
 	return candidate
 }
 
+// findNearestNodeImpl works like findEdgeNodeImpl but returns the node with the given key when it is present.
+func (t *AVLTree[KeyT, ValueT]) findNearestNodeImpl(key KeyT, dir int) *node[KeyT, ValueT] {
+	var n, candidate *node[KeyT, ValueT] = t.root, nil
+	for n != nil {
+		cmpRes := t.compare(key, n.key)
+		if cmpRes == 0 {
+			return n
+		}
+		if cmpRes == (1 - 2*dir) {
+			candidate = n
+		}
+		n = n.links[n.getDirection(cmpRes)]
+	}
+	return candidate
+}
+
 func edgeNodeImpl[KeyT any, ValueT any](n *node[KeyT, ValueT], dir int) *node[KeyT, ValueT] {
 	if n == nil {
 		return nil
@@ -343,6 +471,25 @@ func edgeNodeImpl[KeyT any, ValueT any](n *node[KeyT, ValueT], dir int) *node[Ke
 	return n
 }
 
+func (t *AVLTree[KeyT, ValueT]) nodeAt(i uint) *node[KeyT, ValueT] {
+	if i >= t.count {
+		return nil
+	}
+	n := t.root
+	for {
+		leftSize := n.links[0].getSize()
+		if i == leftSize {
+			return n
+		}
+		if i < leftSize {
+			n = n.links[0]
+		} else {
+			i -= leftSize + 1
+			n = n.links[1]
+		}
+	}
+}
+
 func (t *AVLTree[KeyT, ValueT]) lookupNode(key KeyT) *node[KeyT, ValueT] {
 	n := t.root
 	for n != nil {
@@ -358,6 +505,13 @@ func (t *AVLTree[KeyT, ValueT]) lookupNode(key KeyT) *node[KeyT, ValueT] {
 	return n
 }
 
+// maxHeight returns an upper bound of the AVL tree height for the given elements count.
+// It is used for allocation of explicit stacks in non-recursive traversals.
+func maxHeight(count uint) int {
+	height := bits.Len(count)
+	return height + height/2
+}
+
 type nodeEnumerator[NodeT any] func(node *NodeT) bool
 
 func (t *AVLTree[KeyT, ValueT]) enumerateNodes(order EnumerationOrder, f nodeEnumerator[node[KeyT, ValueT]]) {
@@ -366,9 +520,7 @@ func (t *AVLTree[KeyT, ValueT]) enumerateNodes(order EnumerationOrder, f nodeEnu
 		return
 	}
 
-	maxHeight := bits.Len(t.count)
-	maxHeight += maxHeight / 2
-	stack := make([]*node[KeyT, ValueT], maxHeight)
+	stack := make([]*node[KeyT, ValueT], maxHeight(t.count))
 	stackPtr := 0
 	goingDown := true
 	for {
@@ -411,6 +563,8 @@ type AVLTree[KeyT any, ValueT any] struct {
 	root    *node[KeyT, ValueT]
 	count   uint
 	compare Comparator[KeyT]
+	// nil until the tree is shared by Snapshot
+	owner *ownerToken
 }
 
 // NewAVLTree creates a new AVLTree instance with the given Comparator
@@ -447,12 +601,26 @@ func (t *AVLTree[KeyT, ValueT]) Contains(key KeyT) bool {
 	return t.lookupNode(key) != nil
 }
 
+// Get returns a copy of the value associated with the key and true.
+// When key isn't present returns the zero value and false.
+// Unlike Find it never modifies the tree, so it is safe for concurrent readers under a read lock
+// even when the tree has been shared by Snapshot.
+func (t *AVLTree[KeyT, ValueT]) Get(key KeyT) (ValueT, bool) {
+	n := t.lookupNode(key)
+	if n == nil {
+		var zero ValueT
+		return zero, false
+	}
+	return n.value, true
+}
+
 // Find finds element with specific key
 // Returns an pointer on associated with the key value.
-// Value modification by the pointer is safe.
+// Value modification by the pointer is safe until the next Snapshot call.
 // When key isn't present returns nil pointer.
+// After a Snapshot call it copies shared nodes, so it is a modification for concurrency purposes. Use Get for reads.
 func (t *AVLTree[KeyT, ValueT]) Find(key KeyT) *ValueT {
-	n := t.lookupNode(key)
+	n := t.own(t.lookupNode(key))
 	if n != nil {
 		return &n.value
 	}
@@ -461,10 +629,10 @@ func (t *AVLTree[KeyT, ValueT]) Find(key KeyT) *ValueT {
 
 // FindPrevElement returns a key pointer and a value pointer that is nearest to the given key and lesser then given key.
 // Can return (nil, nil) when no such node in the tree.
-// Value modification by the pointer is safe.
+// Value modification by the pointer is safe until the next Snapshot call.
 // Key modification isn't safe!
 func (t *AVLTree[KeyT, ValueT]) FindPrevElement(key KeyT) (*KeyT, *ValueT) {
-	node := t.findEdgeNodeImpl(key, 0)
+	node := t.own(t.findEdgeNodeImpl(key, 0))
 	if node != nil {
 		return &node.key, &node.value
 	}
@@ -473,24 +641,85 @@ func (t *AVLTree[KeyT, ValueT]) FindPrevElement(key KeyT) (*KeyT, *ValueT) {
 
 // FindNextElement returns a key and a value with the key that is nearest to the given key and greater then given key.
 // Can return (nil, nil) when no such node in the tree.
-// Value modification by the pointer is safe.
+// Value modification by the pointer is safe until the next Snapshot call.
 // Key modification isn't safe!
 func (t *AVLTree[KeyT, ValueT]) FindNextElement(key KeyT) (*KeyT, *ValueT) {
-	node := t.findEdgeNodeImpl(key, 1)
+	node := t.own(t.findEdgeNodeImpl(key, 1))
+	if node != nil {
+		return &node.key, &node.value
+	}
+	return nil, nil
+}
+
+// Floor returns a key pointer and a value pointer for the greatest key that is lesser or equal to the given key.
+// Can return (nil, nil) when no such node in the tree.
+// Value modification by the pointer is safe until the next Snapshot call.
+// Key modification isn't safe!
+func (t *AVLTree[KeyT, ValueT]) Floor(key KeyT) (*KeyT, *ValueT) {
+	node := t.own(t.findNearestNodeImpl(key, 0))
+	if node != nil {
+		return &node.key, &node.value
+	}
+	return nil, nil
+}
+
+// Ceiling returns a key pointer and a value pointer for the least key that is greater or equal to the given key.
+// Can return (nil, nil) when no such node in the tree.
+// Value modification by the pointer is safe until the next Snapshot call.
+// Key modification isn't safe!
+func (t *AVLTree[KeyT, ValueT]) Ceiling(key KeyT) (*KeyT, *ValueT) {
+	node := t.own(t.findNearestNodeImpl(key, 1))
 	if node != nil {
 		return &node.key, &node.value
 	}
 	return nil, nil
 }
 
+// Rank returns the number of elements these keys are lesser than the given key.
+// The given key not necessary has been stored in the tree.
+// Complexity is logarithmic.
+func (t *AVLTree[KeyT, ValueT]) Rank(key KeyT) uint {
+	rank := uint(0)
+	for n := t.root; n != nil; {
+		cmpRes := t.compare(key, n.key)
+		if cmpRes == 0 {
+			return rank + n.links[0].getSize()
+		}
+		if cmpRes > 0 {
+			rank += n.links[0].getSize() + 1
+		}
+		n = n.links[n.getDirection(cmpRes)]
+	}
+	return rank
+}
+
+// At returns key, value pointers for the i-th smallest element. Index is zero-based.
+// Returns (nil, nil) when i is out of range.
+// Complexity is logarithmic.
+// Value modification by the pointer is safe until the next Snapshot call.
+// Key modification isn't safe!
+func (t *AVLTree[KeyT, ValueT]) At(i uint) (*KeyT, *ValueT) {
+	node := t.own(t.nodeAt(i))
+	if node == nil {
+		return nil, nil
+	}
+	return &node.key, &node.value
+}
+
 // Insert inserts an element with the given key and value.
-// It the given key is already present returns an error.
+// It the given key is already present returns ErrKeyExists.
+// Unlike other errors it isn't wrapped into KeyError since the caller already knows the key,
+// so a failed Insert doesn't allocate. Check it via errors.Is, errors.As with KeyError doesn't match:
+//
+//	if err := tree.Insert(key, value); errors.Is(err, ErrKeyExists) {
+//		// 'key' is already present
+//	}
 func (t *AVLTree[KeyT, ValueT]) Insert(key KeyT, value ValueT) error {
-	if avlInsert(&t.root, key, value, t.compare) {
+	if _, ok := avlInsert(&t.root, key, value, nil, t.compare, t.owner); ok {
 		t.count++
 		return nil
 	}
-	return errors.New("AVLTree: already contains key")
+	return ErrKeyExists
 }
 
 // EnumerationOrder  a type of enumeration for Enumerate, EnumerateDiapason methods
@@ -507,10 +736,10 @@ const (
 
 // First returns key, value pointers for the first tree node.
 // Returns (nil, nil) when a tree is empty.
-// Value modification by the pointer is safe.
+// Value modification by the pointer is safe until the next Snapshot call.
 // Key modification isn't safe!
 func (t *AVLTree[KeyT, ValueT]) First() (*KeyT, *ValueT) {
-	node := edgeNodeImpl(t.root, ASCENDING)
+	node := t.own(edgeNodeImpl(t.root, ASCENDING))
 	if node == nil {
 		return nil, nil
 	}
@@ -519,10 +748,10 @@ func (t *AVLTree[KeyT, ValueT]) First() (*KeyT, *ValueT) {
 
 // Last returns key, value pointers for the last tree node
 // Returns (nil, nil) when a tree is empty
-// Value modification by the pointer is safe.
+// Value modification by the pointer is safe until the next Snapshot call.
 // Key modification isn't safe!
 func (t *AVLTree[KeyT, ValueT]) Last() (*KeyT, *ValueT) {
-	node := edgeNodeImpl(t.root, DESCENDING)
+	node := t.own(edgeNodeImpl(t.root, DESCENDING))
 	if node == nil {
 		return nil, nil
 	}
@@ -530,19 +759,20 @@ func (t *AVLTree[KeyT, ValueT]) Last() (*KeyT, *ValueT) {
 }
 
 // Erase removes an element by the given key
-// Can return an error when such Key wasn't present.
+// Can return KeyError that wraps ErrKeyNotFound when such Key wasn't present.
 func (t *AVLTree[KeyT, ValueT]) Erase(key KeyT) error {
-	if nil != avlErase(&t.root, key, t.compare) {
+	if nil != avlErase(&t.root, key, t.compare, t.owner) {
 		t.count--
 		return nil
 	}
-	return errors.New("AVLTree: key not found")
+	return &KeyError[KeyT]{Key: key, Err: ErrKeyNotFound}
 }
 
 // Clear removes all tree content
 func (t *AVLTree[KeyT, ValueT]) Clear() {
 	t.root = nil
 	t.count = 0
+	t.owner = nil
 }
 
 // Enumerate calls 'Enumerator' for every Tree's element.
@@ -558,7 +788,7 @@ func (t *AVLTree[KeyT, ValueT]) Enumerate(order EnumerationOrder, f Enumerator[K
 // EnumerateDiapason works like Enumerate but has two additional args - left and right
 // These are left and right borders for enumeration.
 // Enumeration includes left and right borders.
-// Note: left must be always lesser than right. Otherwise returns error
+// Note: left must be always lesser than right. Otherwise returns RangeError that wraps ErrInvalidRange
 // Note: left and right should be nil. In means the lesser/greater key in the tree is a border.
 //       So call EnumerateDiapason where both borders are nil is equivalent to call Enumerate.
 // Note: If you want to enumerate whole tree call Enumerate since it`s faster!
@@ -568,7 +798,7 @@ func (t *AVLTree[KeyT, ValueT]) EnumerateDiapason(left, right *KeyT, order Enume
 	}
 
 	if left != nil && right != nil && t.compare(*left, *right) > 0 {
-		return errors.New("AVLTree: left must be less rigth")
+		return &RangeError[KeyT]{Left: *left, Right: *right}
 	}
 
 	//find common sub-tree
@@ -586,9 +816,7 @@ func (t *AVLTree[KeyT, ValueT]) EnumerateDiapason(left, right *KeyT, order Enume
 	}
 
 	fences := [2]*KeyT{left, right}
-	maxHeight := bits.Len(t.count)
-	maxHeight += maxHeight / 2
-	stack := make([]*node[KeyT, ValueT], maxHeight)
+	stack := make([]*node[KeyT, ValueT], maxHeight(t.count))
 	stackPtr := 0
 	goingDown := true
 loop:
@@ -1,6 +1,7 @@
 package avltree
 
 import (
+	"math/rand"
 	"strings"
 	"testing"
 
@@ -156,6 +157,29 @@ func TestErase(t *testing.T) {
 	implEraseTesting(t, keysCase3, 3)
 }
 
+func TestEraseRandom(t *testing.T) {
+	require := require.New(t)
+
+	rnd := rand.New(rand.NewSource(1))
+	tree := NewAVLTreeOrderedKey[int, int]()
+	keys := rnd.Perm(5000)
+	for _, k := range keys {
+		require.Nil(tree.Insert(k, k))
+	}
+	rnd.Shuffle(len(keys), func(i, j int) {
+		keys[i], keys[j] = keys[j], keys[i]
+	})
+	for i, k := range keys {
+		require.Nil(tree.Erase(k))
+		if i%250 == 0 {
+			tree.checkHeight(func(hl int, hr int) {
+				require.LessOrEqual(abs(hl-hr), 1)
+			})
+		}
+	}
+	require.True(tree.Empty())
+}
+
 func TestClear(t *testing.T) {
 	require := require.New(t)
 
@@ -231,6 +255,40 @@ func TestFindExt(t *testing.T) {
 	}
 }
 
+func TestFloorCeiling(t *testing.T) {
+	require := require.New(t)
+
+	emptyTree := NewAVLTreeOrderedKey[int, int]()
+	require.Nil(emptyTree.Floor(0))
+	require.Nil(emptyTree.Ceiling(0))
+
+	const (
+		START  = 0
+		FINISH = 100
+		STEP   = 5
+	)
+
+	tree := createTestTree(START, FINISH, STEP)
+	require.Nil(tree.Floor(START - 1))
+	require.Nil(tree.Ceiling(FINISH + 1))
+
+	for i := START; i <= FINISH; i += STEP {
+		k, v := tree.Floor(i)
+		require.Equal(i, *k)
+		require.Equal(i, *v)
+		k, _ = tree.Ceiling(i)
+		require.Equal(i, *k)
+		for j := 1; j < STEP; j++ {
+			k, _ = tree.Floor(i + j)
+			require.Equal(i, *k)
+			if i != START {
+				k, _ = tree.Ceiling(i - j)
+				require.Equal(i, *k)
+			}
+		}
+	}
+}
+
 func TestEnumerate(t *testing.T) {
 	require := require.New(t)
 
@@ -457,3 +515,75 @@ func TestBSTDump(t *testing.T) {
 
 	require.Equal(expected, builder.String())
 }
+
+func checkSize[KeyT any, ValueT any](n *node[KeyT, ValueT]) uint {
+	if n == nil {
+		return 0
+	}
+	size := checkSize(n.links[0]) + checkSize(n.links[1]) + 1
+	if size != n.size {
+		panic("wrong subtree size")
+	}
+	return size
+}
+
+func TestRankAt(t *testing.T) {
+	require := require.New(t)
+
+	emptyTree := NewAVLTreeOrderedKey[int, int]()
+	require.Equal(uint(0), emptyTree.Rank(10))
+	k, v := emptyTree.At(0)
+	require.Nil(k)
+	require.Nil(v)
+
+	const (
+		START  = 0
+		FINISH = 100
+		STEP   = 5
+	)
+
+	tree := createTestTree(START, FINISH, STEP)
+	require.Equal(tree.Size(), checkSize(tree.root))
+
+	for i := START; i <= FINISH; i += STEP {
+		rank := uint(i / STEP)
+		require.Equal(rank, tree.Rank(i))
+		require.Equal(rank+1, tree.Rank(i+1))
+		require.Equal(rank, tree.Rank(i-1))
+
+		k, v := tree.At(rank)
+		require.Equal(i, *k)
+		require.Equal(i, *v)
+	}
+	k, v = tree.At(tree.Size())
+	require.Nil(k)
+	require.Nil(v)
+}
+
+func TestSizeBookkeeping(t *testing.T) {
+	require := require.New(t)
+
+	rnd := rand.New(rand.NewSource(1))
+	tree := NewAVLTreeOrderedKey[int, int]()
+	for i := 0; i < 3000; i++ {
+		key := rnd.Intn(200)
+		if rnd.Intn(3) == 0 {
+			tree.Erase(key)
+		} else {
+			tree.Insert(key, key)
+		}
+		require.Equal(tree.Size(), checkSize(tree.root))
+		tree.checkHeight(func(hl int, hr int) {
+			require.LessOrEqual(abs(hl-hr), 1)
+		})
+	}
+
+	i := uint(0)
+	tree.Enumerate(ASCENDING, func(k int, v int) bool {
+		require.Equal(i, tree.Rank(k))
+		key, _ := tree.At(i)
+		require.Equal(k, *key)
+		i++
+		return true
+	})
+}
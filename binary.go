@@ -0,0 +1,330 @@
+package avltree
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Codec converts values of type T into a binary representation and back.
+// It is used by BinarySnapshot for keys and values.
+type Codec[T any] interface {
+	// Encode appends a binary representation of the value to dst and returns the extended buffer.
+	Encode(dst []byte, value T) ([]byte, error)
+	// Decode restores a value from its binary representation.
+	// src must not be retained since it can be reused after the call.
+	Decode(src []byte) (T, error)
+}
+
+// appendUvarint and appendVarint are used instead of binary.AppendUvarint and binary.AppendVarint
+// these are unavailable in Go 1.18
+func appendUvarint(dst []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(dst, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func appendVarint(dst []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(dst, buf[:binary.PutVarint(buf[:], v)]...)
+}
+
+type integerCodec[T constraints.Integer] struct{}
+
+func (integerCodec[T]) signed() bool {
+	var zero T
+	return zero-1 < 0
+}
+
+func (c integerCodec[T]) Encode(dst []byte, value T) ([]byte, error) {
+	if c.signed() {
+		return appendVarint(dst, int64(value)), nil
+	}
+	return appendUvarint(dst, uint64(value)), nil
+}
+
+func (c integerCodec[T]) Decode(src []byte) (T, error) {
+	var value T
+	var n int
+	if c.signed() {
+		var v int64
+		v, n = binary.Varint(src)
+		value = T(v)
+		if int64(value) != v {
+			n = -1
+		}
+	} else {
+		var v uint64
+		v, n = binary.Uvarint(src)
+		value = T(v)
+		if uint64(value) != v {
+			n = -1
+		}
+	}
+	if n <= 0 || n != len(src) {
+		return 0, errors.New("AVLTree: malformed integer")
+	}
+	return value, nil
+}
+
+// IntegerCodec returns a Codec for any integer type. Values are encoded as varints.
+func IntegerCodec[T constraints.Integer]() Codec[T] {
+	return integerCodec[T]{}
+}
+
+type stringCodec struct{}
+
+func (stringCodec) Encode(dst []byte, value string) ([]byte, error) {
+	return append(dst, value...), nil
+}
+
+func (stringCodec) Decode(src []byte) (string, error) {
+	return string(src), nil
+}
+
+// StringCodec returns a Codec for strings.
+func StringCodec() Codec[string] {
+	return stringCodec{}
+}
+
+type bytesCodec struct{}
+
+func (bytesCodec) Encode(dst []byte, value []byte) ([]byte, error) {
+	return append(dst, value...), nil
+}
+
+func (bytesCodec) Decode(src []byte) ([]byte, error) {
+	return append([]byte{}, src...), nil
+}
+
+// BytesCodec returns a Codec for byte slices.
+func BytesCodec() Codec[[]byte] {
+	return bytesCodec{}
+}
+
+type binaryMarshalerCodec[T encoding.BinaryMarshaler, PT interface {
+	*T
+	encoding.BinaryUnmarshaler
+}] struct{}
+
+func (binaryMarshalerCodec[T, PT]) Encode(dst []byte, value T) ([]byte, error) {
+	data, err := value.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, data...), nil
+}
+
+func (binaryMarshalerCodec[T, PT]) Decode(src []byte) (T, error) {
+	var value T
+	err := PT(&value).UnmarshalBinary(src)
+	return value, err
+}
+
+// BinaryMarshalerCodec returns a Codec for types these implement encoding.BinaryMarshaler
+// and encoding.BinaryUnmarshaler by a pointer. For example BinaryMarshalerCodec[time.Time]().
+func BinaryMarshalerCodec[T encoding.BinaryMarshaler, PT interface {
+	*T
+	encoding.BinaryUnmarshaler
+}]() Codec[T] {
+	return binaryMarshalerCodec[T, PT]{}
+}
+
+/*
+ * Binary snapshot format. All integers are little-endian.
+ * header:  magic "AVLT" | version uint8 | elements count uint64
+ * element: key length uvarint | key bytes | value length uvarint | value bytes
+ * trailer: CRC-32 (IEEE) of the header and all elements uint32
+ * Elements are stored in the ascending order.
+ */
+const (
+	snapshotMagic   = "AVLT"
+	snapshotVersion = 1
+)
+
+// BinarySnapshot writes and reads AVLTree content in a compact versioned binary format.
+// It implements io.WriterTo and io.ReaderFrom. Obtain it via AVLTree.Binary.
+type BinarySnapshot[KeyT any, ValueT any] struct {
+	tree       *AVLTree[KeyT, ValueT]
+	keyCodec   Codec[KeyT]
+	valueCodec Codec[ValueT]
+}
+
+// Binary returns a BinarySnapshot for the tree that uses the given codecs for keys and values.
+func (t *AVLTree[KeyT, ValueT]) Binary(keyCodec Codec[KeyT], valueCodec Codec[ValueT]) *BinarySnapshot[KeyT, ValueT] {
+	return &BinarySnapshot[KeyT, ValueT]{
+		tree:       t,
+		keyCodec:   keyCodec,
+		valueCodec: valueCodec,
+	}
+}
+
+// snapshotWriter counts written bytes and computes a checksum.
+type snapshotWriter struct {
+	w     io.Writer
+	n     int64
+	crc   hash.Hash32
+	err   error
+	frame []byte
+}
+
+func (sw *snapshotWriter) write(data []byte) {
+	if sw.err != nil {
+		return
+	}
+	n, err := sw.w.Write(data)
+	sw.n += int64(n)
+	sw.crc.Write(data[:n])
+	sw.err = err
+}
+
+// WriteTo implements io.WriterTo. It writes the whole tree content into w.
+func (s *BinarySnapshot[KeyT, ValueT]) WriteTo(w io.Writer) (int64, error) {
+	sw := &snapshotWriter{w: w, crc: crc32.NewIEEE()}
+
+	var header [len(snapshotMagic) + 1 + 8]byte
+	copy(header[:], snapshotMagic)
+	header[len(snapshotMagic)] = snapshotVersion
+	binary.LittleEndian.PutUint64(header[len(snapshotMagic)+1:], uint64(s.tree.count))
+	sw.write(header[:])
+
+	var data []byte
+	s.tree.Enumerate(ASCENDING, func(key KeyT, value ValueT) bool {
+		if data, sw.err = s.keyCodec.Encode(data[:0], key); sw.err != nil {
+			sw.err = fmt.Errorf("AVLTree: can't encode key: %w", sw.err)
+			return false
+		}
+		sw.frame = append(appendUvarint(sw.frame[:0], uint64(len(data))), data...)
+		if data, sw.err = s.valueCodec.Encode(data[:0], value); sw.err != nil {
+			sw.err = fmt.Errorf("AVLTree: can't encode value: %w", sw.err)
+			return false
+		}
+		sw.frame = append(appendUvarint(sw.frame, uint64(len(data))), data...)
+		sw.write(sw.frame)
+		return sw.err == nil
+	})
+
+	var trailer [4]byte
+	binary.LittleEndian.PutUint32(trailer[:], sw.crc.Sum32())
+	sw.write(trailer[:])
+	return sw.n, sw.err
+}
+
+// snapshotReader counts read bytes and computes a checksum.
+type snapshotReader struct {
+	r   io.Reader
+	n   int64
+	crc hash.Hash32
+	buf bytes.Buffer
+}
+
+func (sr *snapshotReader) Read(p []byte) (int, error) {
+	n, err := sr.r.Read(p)
+	sr.n += int64(n)
+	sr.crc.Write(p[:n])
+	return n, err
+}
+
+func (sr *snapshotReader) ReadByte() (byte, error) {
+	var b [1]byte
+	_, err := io.ReadFull(sr, b[:])
+	return b[0], err
+}
+
+// readFull reads exactly n bytes. Unlike a preallocated buffer it doesn't allocate
+// a huge amount of memory when n is corrupted.
+func (sr *snapshotReader) readFull(n uint64) ([]byte, error) {
+	sr.buf.Reset()
+	if _, err := io.CopyN(&sr.buf, sr, int64(n)); err != nil {
+		return nil, err
+	}
+	return sr.buf.Bytes(), nil
+}
+
+func (sr *snapshotReader) readFrame() ([]byte, error) {
+	length, err := binary.ReadUvarint(sr)
+	if err != nil {
+		return nil, err
+	}
+	if length > 1<<62 {
+		return nil, errors.New("AVLTree: snapshot element length is too big")
+	}
+	return sr.readFull(length)
+}
+
+func snapshotReadError(err error, what string) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("AVLTree: snapshot is truncated while reading %s", what)
+	}
+	return fmt.Errorf("AVLTree: can't read %s: %w", what, err)
+}
+
+// ReadFrom implements io.ReaderFrom. It replaces the tree content by the content read from r.
+// Reading stops right after the snapshot end. The tree must be created by NewAVLTree (or similar)
+// since a Comparator is required for checking of keys order.
+// Since elements are stored sorted the tree is built in the linear time.
+// Note: r is read by small chunks, so wrap it into bufio.Reader when it is a file or a network connection.
+// On any error the tree stays unchanged.
+func (s *BinarySnapshot[KeyT, ValueT]) ReadFrom(r io.Reader) (int64, error) {
+	if s.tree.compare == nil {
+		return 0, errors.New("AVLTree: Comparator isn't defined, create a tree via NewAVLTree before reading")
+	}
+	sr := &snapshotReader{r: r, crc: crc32.NewIEEE()}
+
+	var header [len(snapshotMagic) + 1 + 8]byte
+	if _, err := io.ReadFull(sr, header[:]); err != nil {
+		return sr.n, snapshotReadError(err, "header")
+	}
+	if string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return sr.n, errors.New("AVLTree: not a snapshot, wrong magic")
+	}
+	if version := header[len(snapshotMagic)]; version != snapshotVersion {
+		return sr.n, fmt.Errorf("AVLTree: unsupported snapshot version %d", version)
+	}
+	count := binary.LittleEndian.Uint64(header[len(snapshotMagic)+1:])
+
+	var prev *KeyT
+	i := uint64(0)
+	root, err := buildBalanced(uint(count), func() (key KeyT, value ValueT, err error) {
+		data, err := sr.readFrame()
+		if err != nil {
+			return key, value, snapshotReadError(err, fmt.Sprintf("key at %d", i))
+		}
+		if key, err = s.keyCodec.Decode(data); err != nil {
+			return key, value, fmt.Errorf("AVLTree: can't decode key at %d: %w", i, err)
+		}
+		if prev != nil && s.tree.compare(*prev, key) >= 0 {
+			return key, value, fmt.Errorf("AVLTree: snapshot key at %d isn't greater than the previous one", i)
+		}
+		prev = &key
+		if data, err = sr.readFrame(); err != nil {
+			return key, value, snapshotReadError(err, fmt.Sprintf("value at %d", i))
+		}
+		if value, err = s.valueCodec.Decode(data); err != nil {
+			return key, value, fmt.Errorf("AVLTree: can't decode value at %d: %w", i, err)
+		}
+		i++
+		return key, value, nil
+	})
+	if err != nil {
+		return sr.n, err
+	}
+
+	sum := sr.crc.Sum32()
+	var trailer [4]byte
+	if _, err := io.ReadFull(sr, trailer[:]); err != nil {
+		return sr.n, snapshotReadError(err, "checksum")
+	}
+	if binary.LittleEndian.Uint32(trailer[:]) != sum {
+		return sr.n, errors.New("AVLTree: snapshot checksum mismatch")
+	}
+
+	s.tree.root, s.tree.count, s.tree.owner = root, uint(count), nil
+	return sr.n, nil
+}
@@ -0,0 +1,132 @@
+package avltree
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinarySnapshot(t *testing.T) {
+	require := require.New(t)
+
+	for _, count := range []int{0, 1, 2, 10, 1000} {
+		tree := NewAVLTreeOrderedKey[int, string]()
+		for i := 0; i < count; i++ {
+			tree.Insert(i*3-count, string(rune('a'+i%26)))
+		}
+
+		buf := new(bytes.Buffer)
+		n, err := tree.Binary(IntegerCodec[int](), StringCodec()).WriteTo(buf)
+		require.Nil(err)
+		require.Equal(int64(buf.Len()), n)
+
+		restored := NewAVLTreeOrderedKey[int, string]()
+		restored.Insert(-100000, "must be removed")
+		n, err = restored.Binary(IntegerCodec[int](), StringCodec()).ReadFrom(buf)
+		require.Nil(err)
+		require.Equal(0, buf.Len())
+		require.Greater(n, int64(0))
+		requireValidTree(require, restored)
+		require.Equal(tree.Size(), restored.Size())
+		tree.Enumerate(ASCENDING, func(k int, v string) bool {
+			require.Equal(v, *restored.Find(k))
+			return true
+		})
+	}
+}
+
+func TestBinarySnapshotCodecs(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAVLTree[string, []byte](orderedComparator[string])
+	tree.Insert("b", []byte{1, 2})
+	tree.Insert("a", nil)
+	buf := new(bytes.Buffer)
+	_, err := tree.Binary(StringCodec(), BytesCodec()).WriteTo(buf)
+	require.Nil(err)
+	restored := NewAVLTree[string, []byte](orderedComparator[string])
+	_, err = restored.Binary(StringCodec(), BytesCodec()).ReadFrom(buf)
+	require.Nil(err)
+	require.Equal([]byte{1, 2}, *restored.Find("b"))
+	require.Empty(*restored.Find("a"))
+
+	now := time.Unix(1000, 0).UTC()
+	times := NewAVLTreeOrderedKey[uint8, time.Time]()
+	times.Insert(255, now)
+	buf.Reset()
+	_, err = times.Binary(IntegerCodec[uint8](), BinaryMarshalerCodec[time.Time]()).WriteTo(buf)
+	require.Nil(err)
+	restoredTimes := NewAVLTreeOrderedKey[uint8, time.Time]()
+	_, err = restoredTimes.Binary(IntegerCodec[uint8](), BinaryMarshalerCodec[time.Time]()).ReadFrom(buf)
+	require.Nil(err)
+	require.True(now.Equal(*restoredTimes.Find(255)))
+
+	// Integer overflow
+	_, err = IntegerCodec[int8]().Decode(appendVarint(nil, 1000))
+	require.Error(err)
+}
+
+func TestBinarySnapshotCorrupted(t *testing.T) {
+	require := require.New(t)
+
+	tree := createTestTree(0, 100, 1)
+	buf := new(bytes.Buffer)
+	_, err := tree.Binary(IntegerCodec[int](), IntegerCodec[int]()).WriteTo(buf)
+	require.Nil(err)
+	data := buf.Bytes()
+
+	read := func(data []byte) (*AVLTree[int, int], error) {
+		restored := createTestTree(0, 1, 1)
+		_, err := restored.Binary(IntegerCodec[int](), IntegerCodec[int]()).ReadFrom(bytes.NewReader(data))
+		if err != nil {
+			// The tree must stay unchanged
+			require.Equal(uint(2), restored.Size())
+		}
+		return restored, err
+	}
+
+	_, err = read(data)
+	require.Nil(err)
+
+	// Truncated
+	for _, size := range []int{0, 3, 12, 13, 50, len(data) - 1} {
+		_, err = read(data[:size])
+		require.ErrorContains(err, "truncated")
+	}
+
+	// Wrong magic
+	corrupted := append([]byte{}, data...)
+	corrupted[0] = 'X'
+	_, err = read(corrupted)
+	require.ErrorContains(err, "magic")
+
+	// Wrong version
+	corrupted = append([]byte{}, data...)
+	corrupted[4] = 100
+	_, err = read(corrupted)
+	require.ErrorContains(err, "version")
+
+	// Huge count
+	corrupted = append([]byte{}, data...)
+	corrupted[12] = 0x7f
+	_, err = read(corrupted)
+	require.ErrorContains(err, "truncated")
+
+	// Damaged value
+	corrupted = append([]byte{}, data...)
+	corrupted[len(corrupted)-6]++
+	_, err = read(corrupted)
+	require.ErrorContains(err, "checksum")
+
+	// Unsorted keys
+	unsorted := new(bytes.Buffer)
+	tree = createTestTree(0, 3, 1)
+	_, err = tree.Binary(IntegerCodec[int](), IntegerCodec[int]()).WriteTo(unsorted)
+	require.Nil(err)
+	_, err = NewAVLTreeOrderedKey[int, int]().Binary(IntegerCodec[int](), IntegerCodec[int]()).ReadFrom(bytes.NewReader(unsorted.Bytes()))
+	require.Nil(err)
+	_, err = NewAVLTree[int, int](func(a, b int) int { return -orderedComparator(a, b) }).Binary(IntegerCodec[int](), IntegerCodec[int]()).ReadFrom(bytes.NewReader(unsorted.Bytes()))
+	require.ErrorContains(err, "isn't greater")
+}
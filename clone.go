@@ -0,0 +1,41 @@
+package avltree
+
+func cloneNodes[KeyT any, ValueT any](n *node[KeyT, ValueT], keyCopy func(KeyT) KeyT, valueCopy func(ValueT) ValueT) *node[KeyT, ValueT] {
+	if n == nil {
+		return nil
+	}
+	c := &node[KeyT, ValueT]{
+		key:     n.key,
+		value:   n.value,
+		size:    n.size,
+		balance: n.balance,
+	}
+	if keyCopy != nil {
+		c.key = keyCopy(n.key)
+	}
+	if valueCopy != nil {
+		c.value = valueCopy(n.value)
+	}
+	c.links[0] = cloneNodes(n.links[0], keyCopy, valueCopy)
+	c.links[1] = cloneNodes(n.links[1], keyCopy, valueCopy)
+	return c
+}
+
+// Clone returns a copy of the tree with the same structure, so no rebalancing is needed.
+// Keys and values are copied by the assignment. Use CloneWith for a deep copy.
+// Complexity is linear.
+func (t *AVLTree[KeyT, ValueT]) Clone() *AVLTree[KeyT, ValueT] {
+	return t.CloneWith(nil, nil)
+}
+
+// CloneWith works like Clone but copies keys and values by 'keyCopy' and 'valueCopy' functions.
+// It is useful for trees with pointer keys or values, like ones created by NewAVLTreeOrderedKeyPtr.
+// A copied key must be equal to the original one under the tree Comparator.
+// A nil function means a copying by the assignment.
+func (t *AVLTree[KeyT, ValueT]) CloneWith(keyCopy func(KeyT) KeyT, valueCopy func(ValueT) ValueT) *AVLTree[KeyT, ValueT] {
+	return &AVLTree[KeyT, ValueT]{
+		root:    cloneNodes(t.root, keyCopy, valueCopy),
+		count:   t.count,
+		compare: t.compare,
+	}
+}
@@ -0,0 +1,69 @@
+package avltree
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func bstDumpString[KeyT any, ValueT any](tree *AVLTree[KeyT, ValueT]) string {
+	var sb strings.Builder
+	tree.BSTDump(&sb)
+	return sb.String()
+}
+
+func TestClone(t *testing.T) {
+	require := require.New(t)
+
+	require.True(NewAVLTreeOrderedKey[int, int]().Clone().Empty())
+
+	tree := createTestTree(0, 100, 1)
+	clone := tree.Clone()
+	require.Nil(clone.Validate())
+	require.Equal(bstDumpString(tree), bstDumpString(clone))
+
+	// Trees are independent
+	require.Nil(clone.Erase(50))
+	*clone.Find(10) = -10
+	require.Nil(clone.Insert(200, 200))
+	require.True(tree.Contains(50))
+	require.Equal(10, *tree.Find(10))
+	require.False(tree.Contains(200))
+	require.Equal(uint(101), tree.Size())
+	require.Equal(uint(101), clone.Size())
+	require.Nil(tree.Validate())
+	require.Nil(clone.Validate())
+}
+
+func TestCloneWith(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAVLTreeOrderedKeyPtr[int, *string]()
+	for i := 0; i < 50; i++ {
+		key, value := i, "v"
+		require.Nil(tree.Insert(&key, &value))
+	}
+
+	clone := tree.CloneWith(func(key *int) *int {
+		c := *key
+		return &c
+	}, func(value *string) *string {
+		c := *value
+		return &c
+	})
+	require.Nil(clone.Validate())
+	require.Equal(tree.Size(), clone.Size())
+
+	key := 10
+	**clone.Find(&key) = "changed"
+	require.Equal("v", **tree.Find(&key))
+	k, _ := clone.First()
+	**k = -1
+	k, _ = tree.First()
+	require.Equal(0, **k)
+
+	shallow := tree.Clone()
+	**shallow.Find(&key) = "shared"
+	require.Equal("shared", **tree.Find(&key))
+}
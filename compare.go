@@ -0,0 +1,62 @@
+package avltree
+
+// Equal checks whether two trees have the same content. Trees shapes don't matter.
+// Keys are compared by a Comparator of the tree a, values are compared by 'valueEq'.
+// When valueEq is nil only keys are compared.
+// Trees with different sizes are never equal, so the check is done in the constant time for them.
+// Otherwise both trees are traversed in lockstep, complexity is linear.
+func Equal[KeyT any, ValueT any](a, b *AVLTree[KeyT, ValueT], valueEq func(ValueT, ValueT) bool) bool {
+	if a.count != b.count {
+		return false
+	}
+	if a.root == b.root {
+		// The same tree or a snapshot without modifications
+		return true
+	}
+	ca, cb := a.SeekFirst(), b.SeekFirst()
+	for ; ca.Valid(); ca.Next() {
+		na, nb := ca.stack[len(ca.stack)-1], cb.stack[len(cb.stack)-1]
+		if a.compare(na.key, nb.key) != 0 {
+			return false
+		}
+		if valueEq != nil && !valueEq(na.value, nb.value) {
+			return false
+		}
+		cb.Next()
+	}
+	return true
+}
+
+// CompareTrees compares two trees lexicographically as sequences of elements in the ascending order.
+// Keys are compared by a Comparator of the tree a, values are compared by 'valueCmp' when keys are equal.
+// When valueCmp is nil only keys are compared.
+// Returns -1 when a < b, 1 when a > b and 0 when trees are equal.
+// A tree that is a prefix of the other one is lesser.
+// Complexity is linear.
+func CompareTrees[KeyT any, ValueT any](a, b *AVLTree[KeyT, ValueT], valueCmp func(ValueT, ValueT) int) int {
+	if a.root == b.root {
+		return 0
+	}
+	ca, cb := a.SeekFirst(), b.SeekFirst()
+	for {
+		switch {
+		case !ca.Valid() && !cb.Valid():
+			return 0
+		case !ca.Valid():
+			return -1
+		case !cb.Valid():
+			return 1
+		}
+		na, nb := ca.stack[len(ca.stack)-1], cb.stack[len(cb.stack)-1]
+		if res := a.compare(na.key, nb.key); res != 0 {
+			return res
+		}
+		if valueCmp != nil {
+			if res := valueCmp(na.value, nb.value); res != 0 {
+				return res
+			}
+		}
+		ca.Next()
+		cb.Next()
+	}
+}
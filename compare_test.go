@@ -0,0 +1,56 @@
+package avltree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func intEq(a int, b int) bool {
+	return a == b
+}
+
+func TestEqual(t *testing.T) {
+	require := require.New(t)
+
+	empty := NewAVLTreeOrderedKey[int, int]()
+	require.True(Equal(empty, NewAVLTreeOrderedKey[int, int](), intEq))
+
+	// Different insertion order gives different shapes
+	a := createTestTree(0, 100, 1)
+	b := NewAVLTreeOrderedKey[int, int]()
+	for i := 100; i >= 0; i-- {
+		b.Insert(i, i)
+	}
+	require.NotEqual(bstDumpString(a), bstDumpString(b))
+	require.True(Equal(a, b, intEq))
+	require.True(Equal(a, a.Snapshot(), intEq))
+	require.False(Equal(a, empty, intEq))
+
+	*b.Find(50) = -50
+	require.False(Equal(a, b, intEq))
+	require.True(Equal(a, b, nil))
+
+	b.Erase(50)
+	b.Insert(1000, 0)
+	require.False(Equal(a, b, nil))
+}
+
+func TestCompareTrees(t *testing.T) {
+	require := require.New(t)
+
+	intCmp := orderedComparator[int]
+	empty := NewAVLTreeOrderedKey[int, int]()
+	a := createTestTree(0, 10, 1)
+	require.Equal(0, CompareTrees(a, createTestTree(0, 10, 1), intCmp))
+	require.Equal(1, CompareTrees(a, empty, intCmp))
+	require.Equal(-1, CompareTrees(empty, a, intCmp))
+	require.Equal(-1, CompareTrees(createTestTree(0, 9, 1), a, intCmp))
+	require.Equal(1, CompareTrees(createTestTree(0, 10, 2), a, intCmp))
+
+	b := createTestTree(0, 10, 1)
+	*b.Find(5) = 6
+	require.Equal(-1, CompareTrees(a, b, intCmp))
+	require.Equal(1, CompareTrees(b, a, intCmp))
+	require.Equal(0, CompareTrees(a, b, nil))
+}
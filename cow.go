@@ -0,0 +1,80 @@
+package avltree
+
+// ownerToken identifies a tree that is allowed to modify a node in place.
+// It isn't empty since pointers to different zero-size variables can be equal.
+type ownerToken struct {
+	_ byte
+}
+
+// ownNode returns *nodePtr when it belongs to the owner.
+// Otherwise it replaces *nodePtr by an owned copy and returns the copy.
+// The node that holds nodePtr must already belong to the owner.
+func ownNode[KeyT any, ValueT any](nodePtr **node[KeyT, ValueT], owner *ownerToken) *node[KeyT, ValueT] {
+	n := *nodePtr
+	if n.owner != owner {
+		c := *n
+		c.owner = owner
+		n = &c
+		*nodePtr = n
+	}
+	return n
+}
+
+// own returns a node of this tree that is safe for modification.
+// Copying is needed only for a shared node, then all nodes from the root to it are copied.
+// Since copying always goes from the root, all ancestors of an owned node are owned too.
+func (t *AVLTree[KeyT, ValueT]) own(n *node[KeyT, ValueT]) *node[KeyT, ValueT] {
+	if n == nil || n.owner == t.owner {
+		return n
+	}
+	nodePtr := &t.root
+	for {
+		c := ownNode(nodePtr, t.owner)
+		cmpRes := t.compare(n.key, c.key)
+		if cmpRes == 0 {
+			return c
+		}
+		nodePtr = &c.links[c.getDirection(cmpRes)]
+	}
+}
+
+func ownAllNodes[KeyT any, ValueT any](nodePtr **node[KeyT, ValueT], owner *ownerToken) {
+	if *nodePtr == nil {
+		return
+	}
+	n := ownNode(nodePtr, owner)
+	n.owner = nil
+	ownAllNodes(&n.links[0], owner)
+	ownAllNodes(&n.links[1], owner)
+}
+
+// ownAll makes the tree exclusive: shared nodes are copied and the tree doesn't need an owner token anymore.
+// It is used before operations these reuse nodes without copying, like Split and Join.
+// Complexity is linear for a tree that has been shared by Snapshot and constant otherwise.
+func (t *AVLTree[KeyT, ValueT]) ownAll() {
+	if t.owner == nil {
+		return
+	}
+	ownAllNodes(&t.root, t.owner)
+	t.owner = nil
+}
+
+// Snapshot returns a copy of the tree in the constant time.
+// Both trees share nodes until one of them modifies a node, then the modified node and its ancestors are copied.
+// So modifications of one tree are never visible via the other one.
+// Methods these return value pointers (Find, First, At, GetOrInsert...) also copy nodes since a value can be modified by the pointer.
+// It means such methods are modifications for concurrency purposes: after a Snapshot call readers of a plain AVLTree
+// that share it under an external read lock race with each other. Such readers should use methods these never modify
+// the tree: Get, Contains, Rank, Size, Enumerate, EnumerateDiapason and cursors. Or use SyncAVLTree or an exclusive lock.
+// Snapshot invalidates all value pointers obtained before the call. They point into nodes shared by both trees,
+// so a modification by such pointer is visible via both trees. Obtain a new pointer after the call instead.
+// Split and Join take nodes of shared trees in the linear time.
+func (t *AVLTree[KeyT, ValueT]) Snapshot() *AVLTree[KeyT, ValueT] {
+	t.owner = new(ownerToken)
+	return &AVLTree[KeyT, ValueT]{
+		root:    t.root,
+		count:   t.count,
+		compare: t.compare,
+		owner:   new(ownerToken),
+	}
+}
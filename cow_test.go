@@ -0,0 +1,233 @@
+package avltree
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	tree := createTestTree(0, 100, 1)
+	snapshot := tree.Snapshot()
+	require.Same(tree.root, snapshot.root)
+
+	require.Nil(tree.Erase(50))
+	require.Nil(tree.Insert(200, 200))
+	*tree.Find(10) = -10
+	_, value := tree.First()
+	*value = -1
+
+	require.Equal(uint(101), snapshot.Size())
+	require.True(snapshot.Contains(50))
+	require.False(snapshot.Contains(200))
+	require.Equal(10, *snapshot.Find(10))
+	_, value = snapshot.First()
+	require.Equal(0, *value)
+	require.Nil(snapshot.Validate())
+	require.Nil(tree.Validate())
+
+	// Only a few paths are copied, unchanged subtrees are still shared
+	nodes := map[*node[int, int]]bool{}
+	snapshot.enumerateNodes(ASCENDING, func(n *node[int, int]) bool {
+		nodes[n] = true
+		return true
+	})
+	shared := 0
+	tree.enumerateNodes(ASCENDING, func(n *node[int, int]) bool {
+		if nodes[n] {
+			shared++
+		}
+		return true
+	})
+	require.Greater(shared, 70)
+}
+
+func TestSnapshotInvalidatesPointers(t *testing.T) {
+	require := require.New(t)
+
+	tree := createTestTree(0, 100, 1)
+	old := tree.Find(3)
+	_, oldFirst := tree.First()
+	snapshot := tree.Snapshot()
+
+	// Pointers obtained before Snapshot point into shared nodes, new ones point into copies
+	value := tree.Find(3)
+	require.NotSame(old, value)
+	*value = 999
+	_, first := tree.At(0)
+	require.NotSame(oldFirst, first)
+	*first = -1
+	require.Equal(3, *snapshot.Find(3))
+	_, first = snapshot.First()
+	require.Equal(0, *first)
+	require.Equal(999, *tree.Find(3))
+
+	changes := collectChanges(snapshot, tree, intEq)
+	require.Len(changes, 2)
+}
+
+func TestSnapshotRandom(t *testing.T) {
+	require := require.New(t)
+
+	rnd := rand.New(rand.NewSource(1))
+	trees := []*AVLTree[int, int]{NewAVLTreeOrderedKey[int, int]()}
+	models := []map[int]int{{}}
+	for i := 0; i < 5000; i++ {
+		j := rnd.Intn(len(trees))
+		tree, model := trees[j], models[j]
+		key := rnd.Intn(200)
+		switch rnd.Intn(8) {
+		case 0:
+			if len(trees) < 10 {
+				trees = append(trees, tree.Snapshot())
+				copied := map[int]int{}
+				for k, v := range model {
+					copied[k] = v
+				}
+				models = append(models, copied)
+			}
+		case 1:
+			_, ok := tree.Remove(key)
+			_, exists := model[key]
+			require.Equal(exists, ok)
+			delete(model, key)
+		case 2:
+			if value := tree.Find(key); value != nil {
+				*value = i
+				model[key] = i
+			}
+		case 3:
+			if k, _, ok := tree.PopFirst(); ok {
+				delete(model, k)
+			}
+		case 4:
+			if k, value := tree.At(uint(key) % (tree.Size() + 1)); k != nil {
+				*value = -i
+				model[*k] = -i
+			}
+		case 5:
+			tree.Update(key, func(old int, exists bool) (int, bool) {
+				return old + 1, !exists || old%2 == 0
+			})
+			if old, ok := model[key]; !ok || old%2 == 0 {
+				model[key] = old + 1
+			} else {
+				delete(model, key)
+			}
+		default:
+			tree.InsertOrAssign(key, i)
+			model[key] = i
+		}
+
+		if i%50 == 0 {
+			for j := range trees {
+				requireTreeContent(require, trees[j], models[j])
+			}
+		}
+	}
+	for j := range trees {
+		requireTreeContent(require, trees[j], models[j])
+	}
+}
+
+func TestSnapshotConsumingOperations(t *testing.T) {
+	require := require.New(t)
+
+	tree := createTestTree(0, 99, 1)
+	snapshot := tree.Snapshot()
+	left, right := tree.Split(50)
+	require.Equal(uint(50), left.Size())
+	require.Equal(uint(50), right.Size())
+	require.Nil(left.Validate())
+	require.Nil(right.Validate())
+	require.Equal(uint(100), snapshot.Size())
+	require.Nil(snapshot.Validate())
+
+	leftSnapshot := left.Snapshot()
+	joined, err := Join(left, right)
+	require.Nil(err)
+	require.Nil(joined.Validate())
+	require.Equal(uint(100), joined.Size())
+	require.Equal(uint(50), leftSnapshot.Size())
+	require.Nil(leftSnapshot.Validate())
+
+	other := createTestTree(50, 149, 1)
+	union := Union(joined, other.Snapshot(), nil)
+	require.Equal(uint(150), union.Size())
+	require.Nil(union.Validate())
+	require.Equal(uint(100), other.Size())
+	require.Nil(other.Validate())
+	require.Nil(snapshot.Validate())
+}
+
+func TestSnapshotConcurrentExport(t *testing.T) {
+	require := require.New(t)
+
+	tree := createTestTree(0, 999, 1)
+	snapshot := tree.Snapshot()
+	done := make(chan int)
+	go func() {
+		sum := 0
+		snapshot.Enumerate(ASCENDING, func(key int, value int) bool {
+			sum += value
+			return true
+		})
+		done <- sum
+	}()
+	for i := 0; i < 1000; i += 2 {
+		require.Nil(tree.Erase(i))
+		*tree.Find(i + 1) = 0
+	}
+	require.Equal(999*1000/2, <-done)
+	require.Equal(uint(500), tree.Size())
+}
+
+func TestSnapshotConcurrentReaders(t *testing.T) {
+	require := require.New(t)
+
+	tree := createTestTree(0, 999, 1)
+	snapshot := tree.Snapshot()
+	var lock sync.RWMutex
+	var wg sync.WaitGroup
+	sums := make([]int, 4)
+	counts := make([]int, 4)
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			// Readers share the tree under a read lock while the snapshot is modified
+			lock.RLock()
+			defer lock.RUnlock()
+			for i := 0; i < 1000; i++ {
+				value, ok := tree.Get(i)
+				if ok && tree.Contains(i) && tree.Rank(i) == uint(i) {
+					sums[g] += value
+				}
+			}
+			for c := tree.SeekFirst(); c.Valid(); c.Next() {
+				counts[g]++
+			}
+		}(g)
+	}
+	for i := 0; i < 1000; i += 2 {
+		require.Nil(snapshot.Erase(i))
+		*snapshot.Find(i + 1) = 0
+	}
+	wg.Wait()
+
+	for g := range sums {
+		require.Equal(999*1000/2, sums[g])
+		require.Equal(1000, counts[g])
+	}
+	value, ok := tree.Get(10)
+	require.True(ok)
+	require.Equal(10, value)
+	_, ok = snapshot.Get(10)
+	require.False(ok)
+	require.Equal(uint(1000), tree.Size())
+	require.Equal(uint(500), snapshot.Size())
+}
@@ -0,0 +1,138 @@
+package avltree
+
+// Cursor is a bidirectional iterator over AVLTree elements.
+// It can be obtained via AVLTree.Seek, AVLTree.SeekFirst or AVLTree.SeekLast.
+// Unlike Enumerate a Cursor doesn't invert control so it can be held across function boundaries,
+// what is useful for merge loops and pagination.
+// Note: any tree modification (Insert, Erase, Clear...) invalidates all cursors obtained before.
+// Using of such cursor leads to undefined behaviour.
+type Cursor[KeyT any, ValueT any] struct {
+	// stack holds a path from the tree root to the current node inclusive.
+	// Empty stack means the cursor is out of the tree bounds.
+	stack []*node[KeyT, ValueT]
+}
+
+func (t *AVLTree[KeyT, ValueT]) newCursor() *Cursor[KeyT, ValueT] {
+	return &Cursor[KeyT, ValueT]{
+		stack: make([]*node[KeyT, ValueT], 0, maxHeight(t.count)+1),
+	}
+}
+
+// edge moves the cursor down from the current node as deep as possible via dir link.
+func (c *Cursor[KeyT, ValueT]) edge(n *node[KeyT, ValueT], dir int) {
+	for ; n != nil; n = n.links[dir] {
+		c.stack = append(c.stack, n)
+	}
+}
+
+// step moves the cursor to the in-order neighbour.
+// dir == 1 means the next node, dir == 0 means the previous one.
+func (c *Cursor[KeyT, ValueT]) step(dir int) bool {
+	if len(c.stack) == 0 {
+		return false
+	}
+	n := c.stack[len(c.stack)-1]
+	if next := n.links[dir]; next != nil {
+		c.edge(next, 1-dir)
+		return true
+	}
+	// Going up until we come from the opposite side
+	for {
+		child := c.stack[len(c.stack)-1]
+		c.stack = c.stack[:len(c.stack)-1]
+		if len(c.stack) == 0 {
+			return false
+		}
+		if c.stack[len(c.stack)-1].links[1-dir] == child {
+			return true
+		}
+	}
+}
+
+// SeekFirst returns a cursor that points to the first tree element.
+// When the tree is empty the cursor is invalid.
+func (t *AVLTree[KeyT, ValueT]) SeekFirst() *Cursor[KeyT, ValueT] {
+	c := t.newCursor()
+	c.edge(t.root, ASCENDING)
+	return c
+}
+
+// SeekLast returns a cursor that points to the last tree element.
+// When the tree is empty the cursor is invalid.
+func (t *AVLTree[KeyT, ValueT]) SeekLast() *Cursor[KeyT, ValueT] {
+	c := t.newCursor()
+	c.edge(t.root, DESCENDING)
+	return c
+}
+
+// seek returns a cursor that points to the least element greater than the given key.
+// When 'inclusive' is true an element with the given key is also suitable.
+func (t *AVLTree[KeyT, ValueT]) seek(key KeyT, inclusive bool) *Cursor[KeyT, ValueT] {
+	c := t.newCursor()
+	candidate := 0
+	for n := t.root; n != nil; {
+		c.stack = append(c.stack, n)
+		cmpRes := t.compare(key, n.key)
+		if cmpRes == 0 {
+			if inclusive {
+				return c
+			}
+			cmpRes = 1
+		}
+		if cmpRes < 0 {
+			candidate = len(c.stack)
+		}
+		n = n.links[n.getDirection(cmpRes)]
+	}
+	c.stack = c.stack[:candidate]
+	return c
+}
+
+// Seek returns a cursor that points to the element with the given key.
+// When such key isn't present the cursor points to the nearest element that is greater than the given key.
+// When all keys in the tree are lesser than the given key the cursor is invalid.
+func (t *AVLTree[KeyT, ValueT]) Seek(key KeyT) *Cursor[KeyT, ValueT] {
+	return t.seek(key, true)
+}
+
+// LowerBound returns a cursor that points to the first element with a key that is greater or equal to the given key.
+// It is the same as Seek.
+// When all keys in the tree are lesser than the given key the cursor is invalid.
+func (t *AVLTree[KeyT, ValueT]) LowerBound(key KeyT) *Cursor[KeyT, ValueT] {
+	return t.seek(key, true)
+}
+
+// UpperBound returns a cursor that points to the first element with a key that is greater than the given key.
+// When all keys in the tree are lesser or equal to the given key the cursor is invalid.
+func (t *AVLTree[KeyT, ValueT]) UpperBound(key KeyT) *Cursor[KeyT, ValueT] {
+	return t.seek(key, false)
+}
+
+// Valid checks whether the cursor points to a tree element.
+func (c *Cursor[KeyT, ValueT]) Valid() bool {
+	return len(c.stack) != 0
+}
+
+// Next moves the cursor to the next element in the ascending order.
+// Returns false when there is no next element. The cursor becomes invalid in this case.
+func (c *Cursor[KeyT, ValueT]) Next() bool {
+	return c.step(1)
+}
+
+// Prev moves the cursor to the previous element in the ascending order.
+// Returns false when there is no previous element. The cursor becomes invalid in this case.
+func (c *Cursor[KeyT, ValueT]) Prev() bool {
+	return c.step(0)
+}
+
+// Key returns a key of the current element.
+// Panics when the cursor is invalid.
+func (c *Cursor[KeyT, ValueT]) Key() KeyT {
+	return c.stack[len(c.stack)-1].key
+}
+
+// Value returns a value of the current element.
+// Panics when the cursor is invalid.
+func (c *Cursor[KeyT, ValueT]) Value() ValueT {
+	return c.stack[len(c.stack)-1].value
+}
@@ -0,0 +1,148 @@
+package avltree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorEmpty(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAVLTreeOrderedKey[int, int]()
+
+	require.False(tree.SeekFirst().Valid())
+	require.False(tree.SeekLast().Valid())
+	c := tree.Seek(10)
+	require.False(c.Valid())
+	require.False(c.Next())
+	require.False(c.Prev())
+}
+
+func TestCursorIteration(t *testing.T) {
+	require := require.New(t)
+
+	const (
+		START  = 0
+		FINISH = 100
+		STEP   = 5
+	)
+
+	tree := createTestTree(START, FINISH, STEP)
+
+	i := START
+	for c := tree.SeekFirst(); c.Valid(); c.Next() {
+		require.Equal(i, c.Key())
+		require.Equal(i, c.Value())
+		i += STEP
+	}
+	require.Equal(FINISH+STEP, i)
+
+	i = FINISH
+	for c := tree.SeekLast(); c.Valid(); c.Prev() {
+		require.Equal(i, c.Key())
+		i -= STEP
+	}
+	require.Equal(START-STEP, i)
+
+	c := tree.SeekFirst()
+	require.False(c.Prev())
+	require.False(c.Valid())
+
+	c = tree.SeekLast()
+	require.False(c.Next())
+	require.False(c.Valid())
+}
+
+func TestCursorSeek(t *testing.T) {
+	require := require.New(t)
+
+	const (
+		START  = 0
+		FINISH = 100
+		STEP   = 5
+	)
+
+	tree := createTestTree(START, FINISH, STEP)
+
+	for i := START; i <= FINISH; i += STEP {
+		c := tree.Seek(i)
+		require.True(c.Valid())
+		require.Equal(i, c.Key())
+
+		c = tree.Seek(i - 1)
+		require.True(c.Valid())
+		require.Equal(i, c.Key())
+
+		// Change direction a few times
+		require.Equal(i != FINISH, c.Next())
+		if i != FINISH {
+			require.Equal(i+STEP, c.Key())
+			require.True(c.Prev())
+			require.Equal(i, c.Key())
+		} else {
+			require.False(c.Valid())
+			c = tree.Seek(i)
+		}
+		require.Equal(i != START, c.Prev())
+		if i != START {
+			require.Equal(i-STEP, c.Key())
+		}
+	}
+
+	require.False(tree.Seek(FINISH + 1).Valid())
+}
+
+func TestCursorBounds(t *testing.T) {
+	require := require.New(t)
+
+	const (
+		START  = 0
+		FINISH = 100
+		STEP   = 5
+	)
+
+	tree := createTestTree(START, FINISH, STEP)
+	for i := START; i <= FINISH; i += STEP {
+		c := tree.LowerBound(i)
+		require.True(c.Valid())
+		require.Equal(i, c.Key())
+
+		c = tree.UpperBound(i - 1)
+		require.True(c.Valid())
+		require.Equal(i, c.Key())
+
+		c = tree.UpperBound(i)
+		require.Equal(i != FINISH, c.Valid())
+		if i != FINISH {
+			require.Equal(i+STEP, c.Key())
+			require.True(c.Prev())
+			require.Equal(i, c.Key())
+		}
+	}
+
+	require.False(tree.LowerBound(FINISH + 1).Valid())
+	require.False(tree.UpperBound(FINISH).Valid())
+	require.Equal(START, tree.UpperBound(START-1).Key())
+}
+
+func TestCursorMerge(t *testing.T) {
+	require := require.New(t)
+
+	tree1 := createTestTree(0, 100, 2)
+	tree2 := createTestTree(1, 99, 2)
+
+	i := 0
+	c1, c2 := tree1.SeekFirst(), tree2.SeekFirst()
+	for c1.Valid() || c2.Valid() {
+		if !c2.Valid() || (c1.Valid() && c1.Key() < c2.Key()) {
+			require.Equal(i, c1.Key())
+			c1.Next()
+		} else {
+			require.Equal(i, c2.Key())
+			c2.Next()
+		}
+		i++
+	}
+	require.Equal(101, i)
+}
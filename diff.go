@@ -0,0 +1,149 @@
+package avltree
+
+// ChangeKind is a kind of a difference reported by Diff
+type ChangeKind int
+
+const (
+	// Added means the key is present only in the new tree
+	Added ChangeKind = iota
+	// Removed means the key is present only in the old tree
+	Removed
+	// Changed means the key is present in both trees with different values
+	Changed
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "Added"
+	case Removed:
+		return "Removed"
+	case Changed:
+		return "Changed"
+	}
+	return "Unknown"
+}
+
+// Change describes a difference between two trees for a single key.
+// OldValue is zero for Added, NewValue is zero for Removed.
+type Change[KeyT any, ValueT any] struct {
+	Kind     ChangeKind
+	Key      KeyT
+	OldValue ValueT
+	NewValue ValueT
+}
+
+// diffItem is a pending part of a tree traversal: either the whole node subtree or the node itself only.
+type diffItem[KeyT any, ValueT any] struct {
+	n      *node[KeyT, ValueT]
+	single bool
+}
+
+// diffIterator traverses a tree in the ascending order and expands subtrees lazily,
+// so identical subtrees of two trees can be skipped without a traversal.
+type diffIterator[KeyT any, ValueT any] struct {
+	stack []diffItem[KeyT, ValueT]
+}
+
+func newDiffIterator[KeyT any, ValueT any](t *AVLTree[KeyT, ValueT]) *diffIterator[KeyT, ValueT] {
+	it := &diffIterator[KeyT, ValueT]{
+		stack: make([]diffItem[KeyT, ValueT], 0, 2*maxHeight(t.count)+1),
+	}
+	it.push(t.root)
+	return it
+}
+
+func (it *diffIterator[KeyT, ValueT]) push(n *node[KeyT, ValueT]) {
+	if n != nil {
+		it.stack = append(it.stack, diffItem[KeyT, ValueT]{n: n})
+	}
+}
+
+func (it *diffIterator[KeyT, ValueT]) top() *diffItem[KeyT, ValueT] {
+	if len(it.stack) == 0 {
+		return nil
+	}
+	return &it.stack[len(it.stack)-1]
+}
+
+func (it *diffIterator[KeyT, ValueT]) pop() {
+	it.stack = it.stack[:len(it.stack)-1]
+}
+
+// expand replaces the top subtree by its right subtree, its root and its left subtree.
+func (it *diffIterator[KeyT, ValueT]) expand() {
+	n := it.top().n
+	it.pop()
+	it.push(n.links[1])
+	it.stack = append(it.stack, diffItem[KeyT, ValueT]{n: n, single: true})
+	it.push(n.links[0])
+}
+
+// Diff calls 'f' for every difference between oldTree and newTree in the ascending keys order.
+// Values of keys present in both trees are compared by 'valueEq'.
+// When valueEq is nil only keys are compared, so Changed is never reported.
+// Enumeration stops when 'f' returns false.
+// Trees taken by Snapshot share unmodified subtrees. Such subtrees are skipped without a traversal,
+// so the complexity is close to O(d*log(n)) where d is the number of modified nodes.
+// For unrelated trees complexity is linear.
+// Skipping relies on shared nodes being unmodified, so value pointers obtained before Snapshot must not be used.
+func Diff[KeyT any, ValueT any](oldTree, newTree *AVLTree[KeyT, ValueT], valueEq func(ValueT, ValueT) bool, f func(change Change[KeyT, ValueT]) bool) {
+	a, b := newDiffIterator(oldTree), newDiffIterator(newTree)
+	for {
+		ta, tb := a.top(), b.top()
+		if ta == nil && tb == nil {
+			return
+		}
+		if ta != nil && tb != nil && ta.n == tb.n && ta.single == tb.single {
+			// Shared nodes are never modified
+			a.pop()
+			b.pop()
+			continue
+		}
+		if ta != nil && tb != nil && !ta.single && !tb.single {
+			// Expand the bigger subtree first since the smaller one can be a part of it
+			if ta.n.size >= tb.n.size {
+				a.expand()
+			} else {
+				b.expand()
+			}
+			continue
+		}
+		if ta != nil && !ta.single {
+			a.expand()
+			continue
+		}
+		if tb != nil && !tb.single {
+			b.expand()
+			continue
+		}
+
+		var change Change[KeyT, ValueT]
+		cmpRes := 0
+		if ta == nil {
+			cmpRes = 1
+		} else if tb == nil {
+			cmpRes = -1
+		} else {
+			cmpRes = oldTree.compare(ta.n.key, tb.n.key)
+		}
+		switch {
+		case cmpRes < 0:
+			change = Change[KeyT, ValueT]{Kind: Removed, Key: ta.n.key, OldValue: ta.n.value}
+			a.pop()
+		case cmpRes > 0:
+			change = Change[KeyT, ValueT]{Kind: Added, Key: tb.n.key, NewValue: tb.n.value}
+			b.pop()
+		default:
+			change = Change[KeyT, ValueT]{Kind: Changed, Key: tb.n.key, OldValue: ta.n.value, NewValue: tb.n.value}
+			a.pop()
+			b.pop()
+			if valueEq == nil || valueEq(change.OldValue, change.NewValue) {
+				continue
+			}
+		}
+		if !f(change) {
+			return
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package avltree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func collectChanges(oldTree, newTree *AVLTree[int, int], valueEq func(int, int) bool) []Change[int, int] {
+	var changes []Change[int, int]
+	Diff(oldTree, newTree, valueEq, func(change Change[int, int]) bool {
+		changes = append(changes, change)
+		return true
+	})
+	return changes
+}
+
+func TestDiff(t *testing.T) {
+	require := require.New(t)
+
+	empty := NewAVLTreeOrderedKey[int, int]()
+	require.Empty(collectChanges(empty, empty, intEq))
+
+	oldTree := createTestTree(0, 5, 1)
+	newTree := createTestTree(2, 7, 1)
+	*newTree.Find(3) = 30
+	require.Equal([]Change[int, int]{
+		{Kind: Removed, Key: 0, OldValue: 0},
+		{Kind: Removed, Key: 1, OldValue: 1},
+		{Kind: Changed, Key: 3, OldValue: 3, NewValue: 30},
+		{Kind: Added, Key: 6, NewValue: 6},
+		{Kind: Added, Key: 7, NewValue: 7},
+	}, collectChanges(oldTree, newTree, intEq))
+
+	// Only keys are compared without valueEq
+	require.Equal([]Change[int, int]{
+		{Kind: Removed, Key: 0, OldValue: 0},
+		{Kind: Removed, Key: 1, OldValue: 1},
+		{Kind: Added, Key: 6, NewValue: 6},
+		{Kind: Added, Key: 7, NewValue: 7},
+	}, collectChanges(oldTree, newTree, nil))
+
+	require.Len(collectChanges(empty, newTree, intEq), 6)
+	require.Len(collectChanges(oldTree, empty, intEq), 6)
+	require.Equal("Changed", Changed.String())
+
+	count := 0
+	Diff(oldTree, newTree, intEq, func(change Change[int, int]) bool {
+		count++
+		return count < 2
+	})
+	require.Equal(2, count)
+}
+
+func TestDiffRandom(t *testing.T) {
+	require := require.New(t)
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		oldTree, oldModel := createRandomTree(rnd, rnd.Intn(300), 500)
+		newTree, newModel := createRandomTree(rnd, rnd.Intn(300), 500)
+
+		var expected []Change[int, int]
+		for key := 0; key < 500; key++ {
+			oldValue, inOld := oldModel[key]
+			newValue, inNew := newModel[key]
+			switch {
+			case inOld && !inNew:
+				expected = append(expected, Change[int, int]{Kind: Removed, Key: key, OldValue: oldValue})
+			case !inOld && inNew:
+				expected = append(expected, Change[int, int]{Kind: Added, Key: key, NewValue: newValue})
+			case inOld && inNew && oldValue != newValue:
+				expected = append(expected, Change[int, int]{Kind: Changed, Key: key, OldValue: oldValue, NewValue: newValue})
+			}
+		}
+		require.Equal(expected, collectChanges(oldTree, newTree, intEq))
+	}
+}
+
+func TestDiffSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	oldTree := createTestTree(0, 9999, 1)
+	newTree := oldTree.Snapshot()
+	require.Nil(newTree.Erase(100))
+	require.Nil(newTree.Insert(20000, 1))
+	*newTree.Find(5000) = -1
+
+	compared := 0
+	changes := collectChanges(oldTree, newTree, func(a int, b int) bool {
+		compared++
+		return a == b
+	})
+	require.Equal([]Change[int, int]{
+		{Kind: Removed, Key: 100, OldValue: 100},
+		{Kind: Changed, Key: 5000, OldValue: 5000, NewValue: -1},
+		{Kind: Added, Key: 20000, NewValue: 1},
+	}, changes)
+	// Only copied paths are compared
+	require.Less(compared, 200)
+
+	// The result is the same for unrelated trees
+	require.Equal(changes, collectChanges(oldTree.Clone(), newTree.Clone(), intEq))
+}
@@ -0,0 +1,50 @@
+package avltree
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrKeyExists is returned when an inserted key is already present.
+	// Insert returns it as is without any wrapping, so a failed Insert doesn't allocate.
+	ErrKeyExists = errors.New("AVLTree: already contains key")
+	// ErrKeyNotFound is returned when a key isn't present. It is wrapped into KeyError.
+	ErrKeyNotFound = errors.New("AVLTree: key not found")
+	// ErrInvalidRange is returned when a left border is greater than a right one. It is wrapped into RangeError.
+	ErrInvalidRange = errors.New("AVLTree: left must be less than right")
+	// ErrUnexpectedValue is returned by Apply when a value precondition fails. It is wrapped into KeyError.
+	ErrUnexpectedValue = errors.New("AVLTree: unexpected value")
+)
+
+// KeyError describes a failure caused by a specific key.
+// Use errors.Is(err, ErrKeyNotFound) for a reason check and errors.As for the key access.
+type KeyError[KeyT any] struct {
+	Key KeyT
+	Err error
+}
+
+func (e *KeyError[KeyT]) Error() string {
+	return fmt.Sprintf("%v: %v", e.Err, e.Key)
+}
+
+// Unwrap returns the reason of the error
+func (e *KeyError[KeyT]) Unwrap() error {
+	return e.Err
+}
+
+// RangeError describes an invalid range given by left and right borders.
+// It always wraps ErrInvalidRange.
+type RangeError[KeyT any] struct {
+	Left  KeyT
+	Right KeyT
+}
+
+func (e *RangeError[KeyT]) Error() string {
+	return fmt.Sprintf("%v: [%v, %v]", ErrInvalidRange, e.Left, e.Right)
+}
+
+// Unwrap returns ErrInvalidRange
+func (e *RangeError[KeyT]) Unwrap() error {
+	return ErrInvalidRange
+}
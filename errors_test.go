@@ -0,0 +1,55 @@
+package avltree
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors(t *testing.T) {
+	require := require.New(t)
+
+	tree := createTestTree(0, 10, 1)
+	err := tree.Insert(5, 5)
+	require.True(errors.Is(err, ErrKeyExists))
+	require.Equal(ErrKeyExists, err)
+	// Insert doesn't wrap ErrKeyExists, the key is known by the caller
+	var insertErr *KeyError[int]
+	require.False(errors.As(err, &insertErr))
+
+	err = tree.Erase(11)
+	require.True(errors.Is(err, ErrKeyNotFound))
+	var keyErr *KeyError[int]
+	require.True(errors.As(err, &keyErr))
+	require.Equal(11, keyErr.Key)
+	require.Equal("AVLTree: key not found: 11", err.Error())
+
+	left, right := 7, 3
+	err = tree.EnumerateDiapason(&left, &right, ASCENDING, func(key int, value int) bool { return true })
+	require.True(errors.Is(err, ErrInvalidRange))
+	var rangeErr *RangeError[int]
+	require.True(errors.As(err, &rangeErr))
+	require.Equal(7, rangeErr.Left)
+	require.Equal(3, rangeErr.Right)
+	require.Equal("AVLTree: left must be less than right: [7, 3]", err.Error())
+
+	multi := NewAVLMultiTreeOrderedKey[int, int]()
+	require.True(errors.Is(multi.EraseOne(1), ErrKeyNotFound))
+
+	persistent, err := NewPersistentAVLTreeOrderedKey[int, int]().Insert(1, 1)
+	require.Nil(err)
+	_, err = persistent.Insert(1, 1)
+	require.True(errors.Is(err, ErrKeyExists))
+	_, err = persistent.Erase(2)
+	require.True(errors.As(err, &keyErr))
+	require.Equal(2, keyErr.Key)
+}
+
+func TestInsertErrorAllocations(t *testing.T) {
+	tree := createTestTree(0, 10, 1)
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = tree.Insert(5, 5)
+	})
+	require.Equal(t, 0.0, allocs)
+}
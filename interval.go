@@ -0,0 +1,178 @@
+package avltree
+
+import (
+	"errors"
+
+	"golang.org/x/exp/constraints"
+)
+
+// ErrInvalidInterval is returned when an interval Start is greater than its End.
+var ErrInvalidInterval = errors.New("IntervalTree: interval start is greater than end")
+
+// Interval is a closed interval [Start, End]. Start must not be greater than End.
+type Interval[T constraints.Ordered] struct {
+	Start T
+	End   T
+}
+
+// Overlaps checks whether two closed intervals have at least one common point.
+func (iv Interval[T]) Overlaps(other Interval[T]) bool {
+	return iv.Start <= other.End && other.Start <= iv.End
+}
+
+func (iv Interval[T]) valid() bool {
+	return iv.Start <= iv.End
+}
+
+// intervalComparator orders intervals by Start and then by End.
+func intervalComparator[T constraints.Ordered](a Interval[T], b Interval[T]) int {
+	if c := orderedComparator(a.Start, b.Start); c != 0 {
+		return c
+	}
+	return orderedComparator(a.End, b.End)
+}
+
+// intervalValue is a value stored in IntervalTree nodes.
+// maxEnd is the greatest End in the node subtree.
+type intervalValue[T constraints.Ordered, ValueT any] struct {
+	value  ValueT
+	maxEnd T
+}
+
+func (v *intervalValue[T, ValueT]) augment(n *node[Interval[T], intervalValue[T, ValueT]]) {
+	v.maxEnd = n.key.End
+	for _, child := range n.links {
+		if child != nil && child.value.maxEnd > v.maxEnd {
+			v.maxEnd = child.value.maxEnd
+		}
+	}
+}
+
+// IntervalTree is a sorted associative container where keys are closed intervals.
+// Intervals are unique and sorted by Start and then by End.
+// Every node additionally keeps the greatest End of its subtree. It is updated by rotations, insertion and erasing,
+// so queries by overlapping skip subtrees these can't contain a result.
+// Search, removal, and insertion operations have logarithmic complexity.
+// As usual IntervalTree instance creation is allowed via `NewIntervalTree`
+type IntervalTree[T constraints.Ordered, ValueT any] struct {
+	tree *AVLTree[Interval[T], intervalValue[T, ValueT]]
+}
+
+// NewIntervalTree creates a new empty IntervalTree instance
+func NewIntervalTree[T constraints.Ordered, ValueT any]() *IntervalTree[T, ValueT] {
+	return &IntervalTree[T, ValueT]{
+		tree: NewAVLTree[Interval[T], intervalValue[T, ValueT]](intervalComparator[T]),
+	}
+}
+
+// Size returns the number of intervals
+func (t *IntervalTree[T, ValueT]) Size() uint {
+	return t.tree.Size()
+}
+
+// Empty checks whether the container is empty
+func (t *IntervalTree[T, ValueT]) Empty() bool {
+	return t.tree.Empty()
+}
+
+// Contains checks if the container contains exactly the given interval
+func (t *IntervalTree[T, ValueT]) Contains(iv Interval[T]) bool {
+	return t.tree.Contains(iv)
+}
+
+// Find finds the value associated with exactly the given interval.
+// Returns nil when the interval isn't present.
+func (t *IntervalTree[T, ValueT]) Find(iv Interval[T]) *ValueT {
+	if value := t.tree.Find(iv); value != nil {
+		return &value.value
+	}
+	return nil
+}
+
+// Insert inserts the given interval with the associated value.
+// Returns ErrKeyExists when the interval is already present or ErrInvalidInterval when its Start is greater than End.
+func (t *IntervalTree[T, ValueT]) Insert(iv Interval[T], value ValueT) error {
+	if !iv.valid() {
+		return ErrInvalidInterval
+	}
+	return t.tree.Insert(iv, intervalValue[T, ValueT]{value: value, maxEnd: iv.End})
+}
+
+// Erase removes the given interval.
+// Can return an error when such interval wasn't present.
+func (t *IntervalTree[T, ValueT]) Erase(iv Interval[T]) error {
+	return t.tree.Erase(iv)
+}
+
+// Clear removes all tree content
+func (t *IntervalTree[T, ValueT]) Clear() {
+	t.tree.Clear()
+}
+
+// Enumerate calls 'Enumerator' for every interval.
+// See AVLTree.Enumerate for the details.
+func (t *IntervalTree[T, ValueT]) Enumerate(order EnumerationOrder, f Enumerator[Interval[T], ValueT]) {
+	t.tree.Enumerate(order, func(iv Interval[T], value intervalValue[T, ValueT]) bool {
+		return f(iv, value.value)
+	})
+}
+
+func overlappingNodes[T constraints.Ordered, ValueT any](n *node[Interval[T], intervalValue[T, ValueT]], iv Interval[T], f Enumerator[Interval[T], ValueT]) bool {
+	if n == nil || n.value.maxEnd < iv.Start {
+		// Every interval in the subtree ends before iv
+		return true
+	}
+	if !overlappingNodes(n.links[0], iv, f) {
+		return false
+	}
+	if n.key.Start > iv.End {
+		// The node and its right subtree start after iv
+		return true
+	}
+	if n.key.End >= iv.Start && !f(n.key, n.value.value) {
+		return false
+	}
+	return overlappingNodes(n.links[1], iv, f)
+}
+
+// Overlapping calls 'Enumerator' for every interval that overlaps the given one in the ascending order.
+// Enumeration stops when 'f' returns false.
+// Returns ErrInvalidInterval when the given interval Start is greater than End.
+// Complexity is O(k*log(n)) where k is the number of reported intervals.
+func (t *IntervalTree[T, ValueT]) Overlapping(iv Interval[T], f Enumerator[Interval[T], ValueT]) error {
+	if !iv.valid() {
+		return ErrInvalidInterval
+	}
+	overlappingNodes(t.tree.root, iv, f)
+	return nil
+}
+
+// Stabbing calls 'Enumerator' for every interval that contains the given point in the ascending order.
+// Enumeration stops when 'f' returns false.
+func (t *IntervalTree[T, ValueT]) Stabbing(point T, f Enumerator[Interval[T], ValueT]) {
+	overlappingNodes(t.tree.root, Interval[T]{Start: point, End: point}, f)
+}
+
+// AnyOverlap returns some interval that overlaps the given one and its value.
+// Returns (nil, nil) when there is no such interval or the given interval Start is greater than End.
+// Complexity is logarithmic.
+// Key modification isn't safe!
+func (t *IntervalTree[T, ValueT]) AnyOverlap(iv Interval[T]) (*Interval[T], *ValueT) {
+	if !iv.valid() {
+		return nil, nil
+	}
+	n := t.tree.root
+	for n != nil {
+		if n.key.Overlaps(iv) {
+			return &n.key, &n.value.value
+		}
+		// When the left subtree reaches iv.Start but has no overlap then all its intervals
+		// start after iv, so the right subtree also has no overlap.
+		if left := n.links[0]; left != nil && left.value.maxEnd >= iv.Start {
+			n = left
+		} else {
+			n = n.links[1]
+		}
+	}
+	return nil, nil
+}
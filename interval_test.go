@@ -0,0 +1,144 @@
+package avltree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// checkMaxEnd returns the greatest End of n subtree. It panics when the augmentation is wrong.
+func checkMaxEnd[ValueT any](n *node[Interval[int], intervalValue[int, ValueT]]) int {
+	maxEnd := n.key.End
+	for _, child := range n.links {
+		if child != nil {
+			maxEnd = max(maxEnd, checkMaxEnd(child))
+		}
+	}
+	if maxEnd != n.value.maxEnd {
+		panic("Wrong max end")
+	}
+	return maxEnd
+}
+
+func TestIntervalTreeModification(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewIntervalTree[int, string]()
+	require.True(tree.Empty())
+	require.Nil(tree.Insert(Interval[int]{1, 5}, "a"))
+	require.Nil(tree.Insert(Interval[int]{1, 3}, "b"))
+	require.Nil(tree.Insert(Interval[int]{4, 4}, "c"))
+	require.Equal(ErrKeyExists, tree.Insert(Interval[int]{1, 5}, "d"))
+	require.Equal(ErrInvalidInterval, tree.Insert(Interval[int]{5, 1}, "e"))
+	require.Equal(uint(3), tree.Size())
+
+	require.True(tree.Contains(Interval[int]{1, 3}))
+	require.False(tree.Contains(Interval[int]{1, 4}))
+	require.Equal("c", *tree.Find(Interval[int]{4, 4}))
+	require.Nil(tree.Find(Interval[int]{4, 5}))
+
+	var order []Interval[int]
+	tree.Enumerate(ASCENDING, func(iv Interval[int], value string) bool {
+		order = append(order, iv)
+		return true
+	})
+	require.Equal([]Interval[int]{{1, 3}, {1, 5}, {4, 4}}, order)
+
+	require.Nil(tree.Erase(Interval[int]{1, 5}))
+	require.Error(tree.Erase(Interval[int]{1, 5}))
+	checkMaxEnd(tree.tree.root)
+	require.Equal(4, tree.tree.root.value.maxEnd)
+
+	tree.Clear()
+	require.True(tree.Empty())
+}
+
+func TestIntervalTreeQueries(t *testing.T) {
+	require := require.New(t)
+
+	rnd := rand.New(rand.NewSource(1))
+	tree := NewIntervalTree[int, int]()
+	model := map[Interval[int]]int{}
+	for i := 0; i < 3000; i++ {
+		start := rnd.Intn(1000)
+		iv := Interval[int]{start, start + rnd.Intn(50)}
+		if rnd.Intn(3) == 0 && len(model) != 0 {
+			for iv = range model {
+				break
+			}
+			require.Nil(tree.Erase(iv))
+			delete(model, iv)
+		} else if _, ok := model[iv]; !ok {
+			require.Nil(tree.Insert(iv, i))
+			model[iv] = i
+		}
+		if tree.tree.root != nil {
+			checkMaxEnd(tree.tree.root)
+		}
+
+		query := Interval[int]{rnd.Intn(1000), 0}
+		query.End = query.Start + rnd.Intn(20)
+		expected := map[Interval[int]]int{}
+		for iv, value := range model {
+			if iv.Overlaps(query) {
+				expected[iv] = value
+			}
+		}
+
+		found := map[Interval[int]]int{}
+		var prev *Interval[int]
+		require.Nil(tree.Overlapping(query, func(iv Interval[int], value int) bool {
+			if prev != nil {
+				require.Equal(-1, intervalComparator(*prev, iv))
+			}
+			prev = &iv
+			found[iv] = value
+			return true
+		}))
+		require.Equal(expected, found)
+
+		anyIv, anyValue := tree.AnyOverlap(query)
+		if len(expected) == 0 {
+			require.Nil(anyIv)
+			require.Nil(anyValue)
+		} else {
+			require.NotNil(anyIv)
+			require.Equal(expected[*anyIv], *anyValue)
+		}
+	}
+	require.Equal(uint(len(model)), tree.Size())
+	tree.tree.checkHeight(func(lh int, rh int) {
+		require.LessOrEqual(lh-rh, 1)
+		require.LessOrEqual(rh-lh, 1)
+	})
+}
+
+func TestIntervalTreeStabbing(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewIntervalTree[int, int]()
+	for i := 0; i < 100; i++ {
+		require.Nil(tree.Insert(Interval[int]{i, i + 9}, i))
+	}
+
+	var values []int
+	tree.Stabbing(50, func(iv Interval[int], value int) bool {
+		values = append(values, value)
+		return true
+	})
+	require.Equal([]int{41, 42, 43, 44, 45, 46, 47, 48, 49, 50}, values)
+
+	values = values[:0]
+	tree.Stabbing(50, func(iv Interval[int], value int) bool {
+		values = append(values, value)
+		return len(values) < 3
+	})
+	require.Equal([]int{41, 42, 43}, values)
+
+	require.Equal(ErrInvalidInterval, tree.Overlapping(Interval[int]{2, 1}, func(iv Interval[int], value int) bool { return true }))
+	iv, _ := tree.AnyOverlap(Interval[int]{200, 300})
+	require.Nil(iv)
+	iv, _ = tree.AnyOverlap(Interval[int]{2, 1})
+	require.Nil(iv)
+}
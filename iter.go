@@ -0,0 +1,52 @@
+//go:build go1.23
+
+package avltree
+
+import (
+	"fmt"
+	"iter"
+)
+
+// All returns an iterator over all tree elements in the ascending order.
+// It is the same as Enumerate with ASCENDING order but suits for `for key, value := range tree.All()`.
+func (t *AVLTree[KeyT, ValueT]) All() iter.Seq2[KeyT, ValueT] {
+	return func(yield func(KeyT, ValueT) bool) {
+		t.Enumerate(ASCENDING, yield)
+	}
+}
+
+// Backward returns an iterator over all tree elements in the descending order.
+// It is the same as Enumerate with DESCENDING order.
+func (t *AVLTree[KeyT, ValueT]) Backward() iter.Seq2[KeyT, ValueT] {
+	return func(yield func(KeyT, ValueT) bool) {
+		t.Enumerate(DESCENDING, yield)
+	}
+}
+
+// Range returns an iterator over tree elements between lo and hi borders in the ascending order.
+// Borders semantic is the same as for EnumerateDiapason: both are included and nil means an open border.
+// Note: when lo is greater than hi the iterator yields nothing.
+func (t *AVLTree[KeyT, ValueT]) Range(lo, hi *KeyT) iter.Seq2[KeyT, ValueT] {
+	return func(yield func(KeyT, ValueT) bool) {
+		t.EnumerateDiapason(lo, hi, ASCENDING, yield)
+	}
+}
+
+// NewAVLTreeFromSortedSeq works like NewAVLTreeFromSorted but takes elements from the given iterator.
+// The iterator must yield keys in the ascending order without duplicates. Otherwise returns an error.
+// The order is checked while reading, so reading stops on the first violating key.
+func NewAVLTreeFromSortedSeq[KeyT any, ValueT any](c Comparator[KeyT], seq iter.Seq2[KeyT, ValueT]) (*AVLTree[KeyT, ValueT], error) {
+	var keys []KeyT
+	var values []ValueT
+	for k, v := range seq {
+		if len(keys) != 0 && c(keys[len(keys)-1], k) >= 0 {
+			return nil, fmt.Errorf("AVLTree: key at %d isn't greater than the previous one", len(keys))
+		}
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+
+	t := NewAVLTree[KeyT, ValueT](c)
+	t.assignSorted(keys, values)
+	return t, nil
+}
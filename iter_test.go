@@ -0,0 +1,110 @@
+//go:build go1.23
+
+package avltree
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterAll(t *testing.T) {
+	require := require.New(t)
+
+	emptyTree := NewAVLTreeOrderedKey[int, int]()
+	for range emptyTree.All() {
+		require.Fail("empty tree iteration")
+	}
+
+	const MIN = -100
+	const MAX = 100
+
+	tree := createTestTree(MIN, MAX, 1)
+
+	i := MIN
+	for k, v := range tree.All() {
+		require.Equal(i, k)
+		require.Equal(i, v)
+		i++
+	}
+	require.Equal(MAX+1, i)
+
+	i = MAX
+	for k := range tree.Backward() {
+		require.Equal(i, k)
+		i--
+	}
+	require.Equal(MIN-1, i)
+
+	// Early break
+	i = MIN
+	for k := range tree.All() {
+		if k == MIN+10 {
+			break
+		}
+		i++
+	}
+	require.Equal(MIN+10, i)
+
+	m := maps.Collect(tree.All())
+	require.Equal(MAX-MIN+1, len(m))
+	require.Equal(MIN, m[MIN])
+}
+
+func TestIterRange(t *testing.T) {
+	require := require.New(t)
+
+	tree := createTestTree(0, 100, 5)
+
+	left := 12
+	right := 53
+	i := 15
+	for k := range tree.Range(&left, &right) {
+		require.Equal(i, k)
+		i += 5
+	}
+	require.Equal(55, i)
+
+	i = 0
+	for k := range tree.Range(nil, &right) {
+		require.Equal(i, k)
+		i += 5
+	}
+	require.Equal(55, i)
+
+	for range tree.Range(&right, &left) {
+		require.Fail("wrong range iteration")
+	}
+}
+
+func TestFromSortedSeq(t *testing.T) {
+	require := require.New(t)
+
+	source := createTestTree(0, 100, 1)
+	tree, err := NewAVLTreeFromSortedSeq(orderedComparator[int], source.All())
+	require.Nil(err)
+	require.Equal(source.Size(), tree.Size())
+	i := 0
+	for k, v := range tree.All() {
+		require.Equal(i, k)
+		require.Equal(i, v)
+		i++
+	}
+
+	_, err = NewAVLTreeFromSortedSeq(orderedComparator[int], source.Backward())
+	require.Error(err)
+
+	// Reading stops on the first violation
+	read := 0
+	_, err = NewAVLTreeFromSortedSeq(orderedComparator[int], func(yield func(int, int) bool) {
+		for i := 0; i < 1000000; i++ {
+			read++
+			if !yield(i%3, i) {
+				return
+			}
+		}
+	})
+	require.Error(err)
+	require.Equal(4, read)
+}
@@ -0,0 +1,204 @@
+package avltree
+
+import "errors"
+
+// ErrJoinOrder is returned by Join when keys of the left tree aren't lesser than keys of the right one.
+var ErrJoinOrder = errors.New("AVLTree: left keys must be less than right keys")
+
+// avlHeight returns a subtree height. It descends via the higher links only, so complexity is logarithmic.
+func avlHeight[KeyT any, ValueT any](n *node[KeyT, ValueT]) int {
+	h := 0
+	for ; n != nil; h++ {
+		if n.balance == 1 {
+			n = n.links[1]
+		} else {
+			n = n.links[0]
+		}
+	}
+	return h
+}
+
+// childHeight returns a height of n.links[dir] where h is a height of n.
+func (n *node[KeyT, ValueT]) childHeight(h int, dir int) int {
+	if n.balance == 1-dir {
+		return h - 2
+	}
+	return h - 1
+}
+
+// avlBalance sets balance of the *pathTop node by the given children heights and rotates it when it is needed.
+// Heights difference must not exceed 2. Returns a new subtree height.
+func avlBalance[KeyT any, ValueT any](pathTop **node[KeyT, ValueT], heights [2]int) int {
+	n := *pathTop
+	n.update()
+	switch heights[1] - heights[0] {
+	case 0:
+		n.balance = -1
+		return heights[0] + 1
+	case 1:
+		n.balance = 1
+		return heights[1] + 1
+	case -1:
+		n.balance = 0
+		return heights[0] + 1
+	}
+
+	dir := 0
+	if heights[1] > heights[0] {
+		dir = 1
+	}
+	h := heights[dir]
+	if n.links[dir].avlIsBalanced() {
+		// Single rotation doesn't decrease the height
+		h++
+	}
+	avlFixup(pathTop, dir)
+	return h
+}
+
+// ownBalance works like avlBalance but takes nodes these are changed by a rotation for the owner.
+// The *pathTop node must already belong to the owner.
+func ownBalance[KeyT any, ValueT any](pathTop **node[KeyT, ValueT], heights [2]int, owner *ownerToken) int {
+	n := *pathTop
+	if diff := heights[1] - heights[0]; diff == 2 || diff == -2 {
+		dir := (diff + 2) >> 2
+		child := ownNode(&n.links[dir], owner)
+		if child.balance == 1-dir {
+			ownNode(&child.links[1-dir], owner)
+		}
+	}
+	return avlBalance(pathTop, heights)
+}
+
+// joinNodes links l, m and r subtrees into the one. Returns a new root and its height.
+// All keys in l must be lesser than m.key and all keys in r must be greater.
+// Changed nodes these don't belong to the owner are copied, so the given subtrees stay untouched.
+func joinNodes[KeyT any, ValueT any](l *node[KeyT, ValueT], hl int, m *node[KeyT, ValueT], r *node[KeyT, ValueT], hr int, owner *ownerToken) (*node[KeyT, ValueT], int) {
+	if hl > hr+1 {
+		return joinSpine(l, hl, m, r, hr, 1, owner)
+	}
+	if hr > hl+1 {
+		return joinSpine(r, hr, m, l, hl, 0, owner)
+	}
+	m = ownNode(&m, owner)
+	m.links[0], m.links[1] = l, r
+	h := ownBalance(&m, [2]int{hl, hr}, owner)
+	return m, h
+}
+
+// joinSpine descends via 'dir' spine of the higher subtree t until it finds a subtree with a height
+// close to the lower subtree height. Then links them via m and rebalances the spine back.
+func joinSpine[KeyT any, ValueT any](t *node[KeyT, ValueT], ht int, m *node[KeyT, ValueT], lower *node[KeyT, ValueT], hlower int, dir int, owner *ownerToken) (*node[KeyT, ValueT], int) {
+	t = ownNode(&t, owner)
+	var heights [2]int
+	heights[1-dir] = t.childHeight(ht, 1-dir)
+	c := t.links[dir]
+	hc := t.childHeight(ht, dir)
+	if hc <= hlower+1 {
+		var mheights [2]int
+		m = ownNode(&m, owner)
+		m.links[1-dir], m.links[dir] = c, lower
+		mheights[1-dir], mheights[dir] = hc, hlower
+		heights[dir] = ownBalance(&m, mheights, owner)
+		t.links[dir] = m
+	} else {
+		t.links[dir], heights[dir] = joinSpine(c, hc, m, lower, hlower, dir, owner)
+	}
+	h := ownBalance(&t, heights, owner)
+	return t, h
+}
+
+// splitNodes splits n subtree with height h on three parts: keys lesser than the given key,
+// the node with the given key (can be nil) and keys greater than the given key.
+// Returns roots with heights. Changed nodes these don't belong to the owner are copied.
+func splitNodes[KeyT any, ValueT any](n *node[KeyT, ValueT], h int, key KeyT, cmp Comparator[KeyT], owner *ownerToken) (l *node[KeyT, ValueT], hl int, m *node[KeyT, ValueT], r *node[KeyT, ValueT], hr int) {
+	if n == nil {
+		return nil, 0, nil, nil, 0
+	}
+	left, hleft := n.links[0], n.childHeight(h, 0)
+	right, hright := n.links[1], n.childHeight(h, 1)
+	cmpRes := cmp(key, n.key)
+	if cmpRes == 0 {
+		n = ownNode(&n, owner)
+		n.links[0], n.links[1] = nil, nil
+		n.size = 1
+		n.balance = -1
+		return left, hleft, n, right, hright
+	}
+	if cmpRes < 0 {
+		l, hl, m, r, hr = splitNodes(left, hleft, key, cmp, owner)
+		r, hr = joinNodes(r, hr, n, right, hright, owner)
+		return
+	}
+	l, hl, m, r, hr = splitNodes(right, hright, key, cmp, owner)
+	l, hl = joinNodes(left, hleft, n, l, hl, owner)
+	return
+}
+
+// splitLast detaches the last node from n subtree with height h.
+// Returns a new root with its height and the detached node.
+func splitLast[KeyT any, ValueT any](n *node[KeyT, ValueT], h int, owner *ownerToken) (*node[KeyT, ValueT], int, *node[KeyT, ValueT]) {
+	if n.links[1] == nil {
+		return n.links[0], n.childHeight(h, 0), n
+	}
+	r, hr, last := splitLast(n.links[1], n.childHeight(h, 1), owner)
+	root, hroot := joinNodes(n.links[0], n.childHeight(h, 0), n, r, hr, owner)
+	return root, hroot, last
+}
+
+// joinNodes2 works like joinNodes but without a middle node.
+func joinNodes2[KeyT any, ValueT any](l *node[KeyT, ValueT], hl int, r *node[KeyT, ValueT], hr int, owner *ownerToken) (*node[KeyT, ValueT], int) {
+	if l == nil {
+		return r, hr
+	}
+	l, hl, m := splitLast(l, hl, owner)
+	return joinNodes(l, hl, m, r, hr, owner)
+}
+
+// Split moves all tree elements into two new trees.
+// The left tree contains keys these are lesser than the given key,
+// the right tree contains keys these are greater or equal to the given key.
+// The original tree becomes empty.
+// Complexity is logarithmic.
+func (t *AVLTree[KeyT, ValueT]) Split(key KeyT) (left, right *AVLTree[KeyT, ValueT]) {
+	t.ownAll()
+	l, _, m, r, hr := splitNodes(t.root, avlHeight(t.root), key, t.compare, nil)
+	if m != nil {
+		r, _ = joinNodes(nil, 0, m, r, hr, nil)
+	}
+	t.Clear()
+
+	left = NewAVLTree[KeyT, ValueT](t.compare)
+	left.root, left.count = l, l.getSize()
+	right = NewAVLTree[KeyT, ValueT](t.compare)
+	right.root, right.count = r, r.getSize()
+	return left, right
+}
+
+// Join moves all elements from the left and right trees into a new tree.
+// All keys in the left tree must be lesser than keys in the right tree. Otherwise returns ErrJoinOrder.
+// Both trees should use the same Comparator and become empty after the call.
+// Complexity is logarithmic.
+func Join[KeyT any, ValueT any](left, right *AVLTree[KeyT, ValueT]) (*AVLTree[KeyT, ValueT], error) {
+	left.ownAll()
+	right.ownAll()
+	result := NewAVLTree[KeyT, ValueT](left.compare)
+	if left.Empty() || right.Empty() {
+		result.root = left.root
+		if left.Empty() {
+			result.root = right.root
+		}
+	} else {
+		l := edgeNodeImpl(left.root, DESCENDING)
+		r := edgeNodeImpl(right.root, ASCENDING)
+		if left.compare(l.key, r.key) >= 0 {
+			return nil, ErrJoinOrder
+		}
+		m := avlErase(&right.root, r.key, right.compare, nil)
+		result.root, _ = joinNodes(left.root, avlHeight(left.root), m, right.root, avlHeight(right.root), nil)
+	}
+	result.count = result.root.getSize()
+	left.Clear()
+	right.Clear()
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package avltree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func checkBalance[KeyT any, ValueT any](n *node[KeyT, ValueT]) int {
+	if n == nil {
+		return 0
+	}
+	hl, hr := checkBalance(n.links[0]), checkBalance(n.links[1])
+	if (hl == hr && n.balance != -1) || (hl > hr && n.balance != 0) || (hl < hr && n.balance != 1) {
+		panic("wrong balance")
+	}
+	return max(hl, hr) + 1
+}
+
+func requireValidTree[ValueT any](require *require.Assertions, tree *AVLTree[int, ValueT]) {
+	require.Equal(tree.Size(), checkSize(tree.root))
+	require.Equal(checkBalance(tree.root), avlHeight(tree.root))
+	tree.checkHeight(func(hl int, hr int) {
+		require.LessOrEqual(abs(hl-hr), 1)
+	})
+	prev := (*int)(nil)
+	tree.Enumerate(ASCENDING, func(k int, v ValueT) bool {
+		if prev != nil {
+			require.Less(*prev, k)
+		}
+		prev = &k
+		return true
+	})
+}
+
+func TestSplit(t *testing.T) {
+	require := require.New(t)
+
+	emptyTree := NewAVLTreeOrderedKey[int, int]()
+	left, right := emptyTree.Split(10)
+	require.True(left.Empty())
+	require.True(right.Empty())
+
+	for count := 1; count < 40; count++ {
+		for key := -1; key <= count*2+1; key++ {
+			tree := createTestTree(0, count*2, 2)
+			left, right := tree.Split(key)
+			require.True(tree.Empty())
+			requireValidTree(require, left)
+			requireValidTree(require, right)
+			require.Equal(uint(count+1), left.Size()+right.Size())
+
+			left.Enumerate(ASCENDING, func(k int, v int) bool {
+				require.Less(k, key)
+				return true
+			})
+			right.Enumerate(ASCENDING, func(k int, v int) bool {
+				require.GreaterOrEqual(k, key)
+				return true
+			})
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	require := require.New(t)
+
+	for lcount := 0; lcount < 40; lcount++ {
+		for rcount := 0; rcount < 40; rcount++ {
+			left := createTestTree(0, lcount-1, 1)
+			right := createTestTree(lcount, lcount+rcount-1, 1)
+			tree, err := Join(left, right)
+			require.Nil(err)
+			require.True(left.Empty())
+			require.True(right.Empty())
+			requireValidTree(require, tree)
+			require.Equal(uint(lcount+rcount), tree.Size())
+
+			// The tree must stay consistent after modifications
+			require.Nil(tree.Insert(-1, -1))
+			require.Nil(tree.Erase(-1))
+			requireValidTree(require, tree)
+		}
+	}
+
+	left := createTestTree(0, 10, 1)
+	right := createTestTree(10, 20, 1)
+	_, err := Join(left, right)
+	require.Equal(ErrJoinOrder, err)
+}
+
+func TestSplitJoin(t *testing.T) {
+	require := require.New(t)
+
+	tree := createTestTree(0, 1000, 1)
+	left, right := tree.Split(333)
+	require.Equal(uint(333), left.Size())
+	tree, err := Join(left, right)
+	require.Nil(err)
+	requireValidTree(require, tree)
+	require.Equal(uint(1001), tree.Size())
+	for i := 0; i <= 1000; i++ {
+		require.Equal(uint(i), tree.Rank(i))
+	}
+}
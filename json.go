@@ -0,0 +1,185 @@
+package avltree
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// jsonEntry is an element representation when a key can't be represented as a JSON object key.
+type jsonEntry[KeyT any, ValueT any] struct {
+	Key   KeyT   `json:"key"`
+	Value ValueT `json:"value"`
+}
+
+// keyIsText checks whether keys are written as JSON object keys.
+func keyIsText[KeyT any]() bool {
+	keyType := reflect.TypeOf((*KeyT)(nil)).Elem()
+	return keyType.Kind() == reflect.String || keyType.Implements(textMarshalerType)
+}
+
+func marshalKeyText[KeyT any](key KeyT) ([]byte, error) {
+	if m, ok := any(key).(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(string(text))
+	}
+	return json.Marshal(reflect.ValueOf(key).String())
+}
+
+func unmarshalKeyText[KeyT any](text string) (KeyT, error) {
+	var key KeyT
+	if u, ok := any(&key).(encoding.TextUnmarshaler); ok {
+		err := u.UnmarshalText([]byte(text))
+		return key, err
+	}
+	value := reflect.ValueOf(&key).Elem()
+	if value.Kind() != reflect.String {
+		return key, fmt.Errorf("AVLTree: can't unmarshal JSON object key into %v", value.Type())
+	}
+	value.SetString(text)
+	return key, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// When KeyT is a string or implements encoding.TextMarshaler the tree is written as a JSON object.
+// Otherwise it is written as an array of {"key":...,"value":...} objects.
+// In both cases elements are written in the ascending order.
+func (t *AVLTree[KeyT, ValueT]) MarshalJSON() ([]byte, error) {
+	textKey := keyIsText[KeyT]()
+	buf := new(bytes.Buffer)
+	if textKey {
+		buf.WriteByte('{')
+	} else {
+		buf.WriteByte('[')
+	}
+
+	var err error
+	first := true
+	t.Enumerate(ASCENDING, func(key KeyT, value ValueT) bool {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		var data []byte
+		if textKey {
+			if data, err = marshalKeyText(key); err != nil {
+				return false
+			}
+			buf.Write(data)
+			buf.WriteByte(':')
+			data, err = json.Marshal(value)
+		} else {
+			data, err = json.Marshal(jsonEntry[KeyT, ValueT]{Key: key, Value: value})
+		}
+		if err != nil {
+			return false
+		}
+		buf.Write(data)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if textKey {
+		buf.WriteByte('}')
+	} else {
+		buf.WriteByte(']')
+	}
+	return buf.Bytes(), nil
+}
+
+func unmarshalJSONObject[KeyT any, ValueT any](data []byte) ([]KeyT, []ValueT, error) {
+	var keys []KeyT
+	var values []ValueT
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// Skip '{'
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, err
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key, err := unmarshalKeyText[KeyT](token.(string))
+		if err != nil {
+			return nil, nil, err
+		}
+		var value ValueT
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	return keys, values, nil
+}
+
+func unmarshalJSONArray[KeyT any, ValueT any](data []byte) ([]KeyT, []ValueT, error) {
+	var entries []jsonEntry[KeyT, ValueT]
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, nil, err
+	}
+	keys := make([]KeyT, len(entries))
+	values := make([]ValueT, len(entries))
+	for i := range entries {
+		keys[i], values[i] = entries[i].Key, entries[i].Value
+	}
+	return keys, values, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It accepts both formats written by MarshalJSON. The JSON object format requires KeyT to be a string
+// or implement encoding.TextUnmarshaler.
+// The tree must be created by NewAVLTree (or similar) before the call since a Comparator is required.
+// The tree content is replaced by the decoded elements. Duplicated keys lead to an error.
+// When elements are already sorted the tree is built in the linear time.
+// JSON null is a no-op like for other json.Unmarshaler implementations, the tree stays unchanged.
+func (t *AVLTree[KeyT, ValueT]) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if t.compare == nil {
+		return errors.New("AVLTree: Comparator isn't defined, create a tree via NewAVLTree before unmarshaling")
+	}
+
+	var keys []KeyT
+	var values []ValueT
+	var err error
+	switch {
+	case bytes.HasPrefix(data, []byte("{")):
+		keys, values, err = unmarshalJSONObject[KeyT, ValueT](data)
+	case bytes.HasPrefix(data, []byte("[")):
+		keys, values, err = unmarshalJSONArray[KeyT, ValueT](data)
+	default:
+		err = errors.New("AVLTree: JSON object or array is expected")
+	}
+	if err != nil {
+		return err
+	}
+
+	if checkSorted(t.compare, keys) == nil {
+		t.assignSorted(keys, values)
+		return nil
+	}
+
+	tree := NewAVLTree[KeyT, ValueT](t.compare)
+	for i := range keys {
+		if tree.Insert(keys[i], values[i]) != nil {
+			return fmt.Errorf("AVLTree: duplicated key at %d in JSON", i)
+		}
+	}
+	t.root, t.count, t.owner = tree.root, tree.count, nil
+	return nil
+}
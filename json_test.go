@@ -0,0 +1,135 @@
+package avltree
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type textKey struct {
+	a, b int
+}
+
+func (k textKey) MarshalText() ([]byte, error) {
+	return []byte(strconv.Itoa(k.a) + "/" + strconv.Itoa(k.b)), nil
+}
+
+func (k *textKey) UnmarshalText(text []byte) error {
+	parts := strings.Split(string(text), "/")
+	k.a, _ = strconv.Atoi(parts[0])
+	k.b, _ = strconv.Atoi(parts[1])
+	return nil
+}
+
+func compareTextKey(x, y textKey) int {
+	if x.a != y.a {
+		return orderedComparator(x.a, y.a)
+	}
+	return orderedComparator(x.b, y.b)
+}
+
+func TestJSONArray(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAVLTreeOrderedKey[int, string]()
+	data, err := json.Marshal(tree)
+	require.Nil(err)
+	require.Equal("[]", string(data))
+
+	tree.Insert(3, "c")
+	tree.Insert(1, "a")
+	tree.Insert(2, "b")
+	data, err = json.Marshal(tree)
+	require.Nil(err)
+	require.Equal(`[{"key":1,"value":"a"},{"key":2,"value":"b"},{"key":3,"value":"c"}]`, string(data))
+
+	restored := NewAVLTreeOrderedKey[int, string]()
+	restored.Insert(10, "must be removed")
+	require.Nil(json.Unmarshal(data, restored))
+	require.Equal(uint(3), restored.Size())
+	require.Equal("b", *restored.Find(2))
+	require.False(restored.Contains(10))
+	requireValidTree(require, restored)
+
+	// Unsorted input
+	require.Nil(json.Unmarshal([]byte(`[{"key":3,"value":"c"},{"key":1,"value":"a"}]`), restored))
+	require.Equal(uint(2), restored.Size())
+	k, _ := restored.First()
+	require.Equal(1, *k)
+
+	require.Error(json.Unmarshal([]byte(`[{"key":3,"value":"c"},{"key":3,"value":"a"}]`), restored))
+	require.Error(json.Unmarshal([]byte(`{"a":"b"}`), restored))
+	require.Error(json.Unmarshal([]byte(`"text"`), restored))
+
+	// null keeps a populated tree unchanged
+	require.Nil(restored.UnmarshalJSON([]byte(" null ")))
+	require.Nil(json.Unmarshal([]byte("null"), restored))
+	require.Equal(uint(2), restored.Size())
+	require.Equal("c", *restored.Find(3))
+
+	var noComparator AVLTree[int, string]
+	require.Error(json.Unmarshal(data, &noComparator))
+	require.Nil(noComparator.UnmarshalJSON([]byte("null")))
+}
+
+func TestJSONObject(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAVLTreeOrderedKey[string, int]()
+	data, err := json.Marshal(tree)
+	require.Nil(err)
+	require.Equal("{}", string(data))
+
+	for i, k := range []string{"z", "b", "a", "y"} {
+		tree.Insert(k, i)
+	}
+	data, err = json.Marshal(tree)
+	require.Nil(err)
+	require.Equal(`{"a":2,"b":1,"y":3,"z":0}`, string(data))
+
+	restored := NewAVLTreeOrderedKey[string, int]()
+	require.Nil(json.Unmarshal(data, restored))
+	require.Equal(uint(4), restored.Size())
+	require.Equal(3, *restored.Find("y"))
+
+	require.Nil(json.Unmarshal([]byte(`{"z":0,"a":1}`), restored))
+	require.Equal(uint(2), restored.Size())
+	require.Error(json.Unmarshal([]byte(`{"z":0,"z":1}`), restored))
+}
+
+func TestJSONTextMarshaler(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAVLTree[textKey, bool](compareTextKey)
+	tree.Insert(textKey{2, 1}, true)
+	tree.Insert(textKey{1, 2}, false)
+
+	data, err := json.Marshal(tree)
+	require.Nil(err)
+	require.Equal(`{"1/2":false,"2/1":true}`, string(data))
+
+	restored := NewAVLTree[textKey, bool](compareTextKey)
+	require.Nil(json.Unmarshal(data, restored))
+	require.Equal(uint(2), restored.Size())
+	require.True(*restored.Find(textKey{2, 1}))
+}
+
+func TestJSONNested(t *testing.T) {
+	require := require.New(t)
+
+	type document struct {
+		Tree *AVLTree[int, int] `json:"tree"`
+	}
+
+	doc := document{Tree: createTestTree(1, 3, 1)}
+	data, err := json.Marshal(doc)
+	require.Nil(err)
+	require.Equal(`{"tree":[{"key":1,"value":1},{"key":2,"value":2},{"key":3,"value":3}]}`, string(data))
+
+	restored := document{Tree: NewAVLTreeOrderedKey[int, int]()}
+	require.Nil(json.Unmarshal(data, &restored))
+	require.Equal(uint(3), restored.Tree.Size())
+}
@@ -0,0 +1,66 @@
+package avltree
+
+import "math/bits"
+
+// mergeByInsertion inserts all elements of 'other' one by one.
+func (t *AVLTree[KeyT, ValueT]) mergeByInsertion(other *AVLTree[KeyT, ValueT], resolve Resolver[KeyT, ValueT]) {
+	other.enumerateNodes(ASCENDING, func(theirs *node[KeyT, ValueT]) bool {
+		mine, inserted := avlInsert(&t.root, theirs.key, theirs.value, nil, t.compare, t.owner)
+		if inserted {
+			t.count++
+		} else if resolve != nil {
+			mine = t.own(mine)
+			mine.value = resolve(mine.key, mine.value, theirs.value)
+		}
+		return true
+	})
+}
+
+// mergeByRebuild merges both trees in lockstep and builds a new balanced tree from the result.
+func (t *AVLTree[KeyT, ValueT]) mergeByRebuild(other *AVLTree[KeyT, ValueT], resolve Resolver[KeyT, ValueT]) {
+	keys := make([]KeyT, 0, t.count+other.count)
+	values := make([]ValueT, 0, t.count+other.count)
+	cm, co := t.SeekFirst(), other.SeekFirst()
+	for cm.Valid() || co.Valid() {
+		cmpRes := -1
+		if !cm.Valid() {
+			cmpRes = 1
+		} else if co.Valid() {
+			cmpRes = t.compare(cm.Key(), co.Key())
+		}
+
+		switch {
+		case cmpRes < 0:
+			keys, values = append(keys, cm.Key()), append(values, cm.Value())
+			cm.Next()
+		case cmpRes > 0:
+			keys, values = append(keys, co.Key()), append(values, co.Value())
+			co.Next()
+		default:
+			value := cm.Value()
+			if resolve != nil {
+				value = resolve(cm.Key(), value, co.Value())
+			}
+			keys, values = append(keys, cm.Key()), append(values, value)
+			cm.Next()
+			co.Next()
+		}
+	}
+	t.assignSorted(keys, values)
+}
+
+// Merge inserts all elements of 'other' into this tree. 'other' isn't modified.
+// When a key is present in both trees 'resolve' chooses a value for it from this tree value and the other one.
+// When resolve is nil a value of this tree is kept.
+// Both trees should use the same Comparator.
+// When 'other' is small its elements are inserted one by one in O(m*log(n+m)), where m is the 'other' size.
+// Otherwise both trees are merged in the linear time and this tree is rebuilt as a perfectly balanced one.
+// Pointers obtained before the call are invalidated by a rebuild.
+func (t *AVLTree[KeyT, ValueT]) Merge(other *AVLTree[KeyT, ValueT], resolve Resolver[KeyT, ValueT]) {
+	total := t.count + other.count
+	if other.count*uint(bits.Len(total)) < total {
+		t.mergeByInsertion(other, resolve)
+	} else {
+		t.mergeByRebuild(other, resolve)
+	}
+}
@@ -0,0 +1,86 @@
+package avltree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sumResolver(key int, mine, theirs int) int {
+	return mine + theirs
+}
+
+func TestMerge(t *testing.T) {
+	require := require.New(t)
+
+	// Small other is inserted element by element
+	tree := createTestTree(0, 999, 2)
+	other := createTestTree(100, 110, 1)
+	tree.Merge(other, sumResolver)
+	require.Equal(uint(505), tree.Size())
+	require.Equal(200, *tree.Find(100))
+	require.Equal(101, *tree.Find(101))
+	require.Nil(tree.Validate())
+	require.Equal(uint(11), other.Size())
+	require.Equal(100, *other.Find(100))
+
+	// Comparable sizes lead to a rebuild
+	tree = createTestTree(0, 99, 2)
+	other = createTestTree(50, 149, 1)
+	tree.Merge(other, nil)
+	require.Equal(uint(125), tree.Size())
+	require.Equal(50, *tree.Find(50))
+	require.Equal(149, *tree.Find(149))
+	require.Nil(tree.Validate())
+	require.Equal(uint(100), other.Size())
+	require.Nil(other.Validate())
+
+	empty := NewAVLTreeOrderedKey[int, int]()
+	tree.Merge(empty, sumResolver)
+	require.Equal(uint(125), tree.Size())
+	empty.Merge(other, sumResolver)
+	require.True(Equal(empty, other, intEq))
+
+	tree.Merge(tree, sumResolver)
+	require.Equal(uint(125), tree.Size())
+	require.Equal(100, *tree.Find(50))
+	require.Nil(tree.Validate())
+}
+
+func TestMergeRandom(t *testing.T) {
+	require := require.New(t)
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		tree, model := createRandomTree(rnd, rnd.Intn(500), 1000)
+		other, otherModel := createRandomTree(rnd, rnd.Intn(50)*rnd.Intn(10), 1000)
+		for k, v := range otherModel {
+			if old, ok := model[k]; ok {
+				model[k] = old + v
+			} else {
+				model[k] = v
+			}
+		}
+		tree.Merge(other, sumResolver)
+		requireTreeContent(require, tree, model)
+		requireTreeContent(require, other, otherModel)
+	}
+}
+
+func TestMergeSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	for _, count := range []int{5, 500} {
+		tree := createTestTree(0, 999, 1)
+		snapshot := tree.Snapshot()
+		other := createTestTree(0, count-1, 1)
+		otherSnapshot := other.Snapshot()
+		tree.Merge(other, sumResolver)
+		require.Equal(uint(1000), tree.Size())
+		require.Equal(8, *tree.Find(4))
+		require.Nil(tree.Validate())
+		require.True(Equal(snapshot, createTestTree(0, 999, 1), intEq))
+		require.True(Equal(other, otherSnapshot, intEq))
+	}
+}
@@ -0,0 +1,182 @@
+package avltree
+
+import (
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+// multiKey makes keys of AVLMultiTree unique. seq is an insertion sequence number that starts from 1.
+type multiKey[KeyT any] struct {
+	key KeyT
+	seq uint64
+}
+
+// AVLMultiTree is a sorted associative container that contains key-value pairs where keys aren't unique.
+// Every element is a separate tree node. Entries with the same key are kept in the insertion order.
+// Keys are sorted by using the comparison function `Comparator`.
+// Search, removal, and insertion operations have logarithmic complexity.
+// As usual AVLMultiTree instance creation is allowed via `NewAVLMultiTreeOrderedKey` or `NewAVLMultiTree`
+type AVLMultiTree[KeyT any, ValueT any] struct {
+	tree    *AVLTree[multiKey[KeyT], ValueT]
+	compare Comparator[KeyT]
+	// The last used insertion sequence number
+	seq uint64
+	// Number of unique keys
+	keys uint
+}
+
+// NewAVLMultiTree creates a new AVLMultiTree instance with the given Comparator
+func NewAVLMultiTree[KeyT any, ValueT any](c Comparator[KeyT]) *AVLMultiTree[KeyT, ValueT] {
+	return &AVLMultiTree[KeyT, ValueT]{
+		tree: NewAVLTree[multiKey[KeyT], ValueT](func(a, b multiKey[KeyT]) int {
+			if cmpRes := c(a.key, b.key); cmpRes != 0 {
+				return cmpRes
+			}
+			return orderedComparator(a.seq, b.seq)
+		}),
+		compare: c,
+	}
+}
+
+// NewAVLMultiTreeOrderedKey creates a new AVLMultiTree instance where Key type is constraints.Ordered.
+// This is actually the same as NewAVLMultiTree but Comparator will be defined automaticaly inside the call.
+func NewAVLMultiTreeOrderedKey[KeyT constraints.Ordered, ValueT any]() *AVLMultiTree[KeyT, ValueT] {
+	return NewAVLMultiTree[KeyT, ValueT](orderedComparator[KeyT])
+}
+
+// Size returns the number of elements including duplicates
+func (t *AVLMultiTree[KeyT, ValueT]) Size() uint {
+	return t.tree.Size()
+}
+
+// KeysCount returns the number of unique keys
+func (t *AVLMultiTree[KeyT, ValueT]) KeysCount() uint {
+	return t.keys
+}
+
+// Empty checks whether the container is empty
+func (t *AVLMultiTree[KeyT, ValueT]) Empty() bool {
+	return t.tree.Empty()
+}
+
+// bounds returns internal keys these are lesser and greater than all elements with the given key.
+func bounds[KeyT any](key KeyT) (multiKey[KeyT], multiKey[KeyT]) {
+	return multiKey[KeyT]{key: key}, multiKey[KeyT]{key: key, seq: math.MaxUint64}
+}
+
+// first returns a cursor that points to the earliest inserted element with the given key.
+// The cursor is invalid when the key isn't present.
+func (t *AVLMultiTree[KeyT, ValueT]) first(key KeyT) *Cursor[multiKey[KeyT], ValueT] {
+	lower, _ := bounds(key)
+	c := t.tree.LowerBound(lower)
+	if c.Valid() && t.compare(key, c.Key().key) != 0 {
+		c.stack = c.stack[:0]
+	}
+	return c
+}
+
+// Contains checks if the container contains at least one element with the specific key
+func (t *AVLMultiTree[KeyT, ValueT]) Contains(key KeyT) bool {
+	return t.first(key).Valid()
+}
+
+// Count returns the number of elements with the specific key.
+// Complexity is logarithmic.
+func (t *AVLMultiTree[KeyT, ValueT]) Count(key KeyT) uint {
+	lower, upper := bounds(key)
+	return t.tree.Rank(upper) - t.tree.Rank(lower)
+}
+
+// FindAll returns a copy of all values associated with the key in the insertion order.
+// Returns nil when key isn't present.
+func (t *AVLMultiTree[KeyT, ValueT]) FindAll(key KeyT) []ValueT {
+	var values []ValueT
+	for c := t.first(key); c.Valid() && t.compare(key, c.Key().key) == 0; c.Next() {
+		values = append(values, c.Value())
+	}
+	return values
+}
+
+// Insert inserts an element with the given key and value.
+// When the given key is already present the value is placed after all existing ones.
+// It performs a single tree descent.
+func (t *AVLMultiTree[KeyT, ValueT]) Insert(key KeyT, value ValueT) {
+	t.seq++
+	// The new element is greater than all duplicates, so the last of them is on the insertion path.
+	duplicate := false
+	avlInsert(&t.tree.root, multiKey[KeyT]{key: key, seq: t.seq}, value, nil, func(a, b multiKey[KeyT]) int {
+		if cmpRes := t.compare(a.key, b.key); cmpRes != 0 {
+			return cmpRes
+		}
+		if a.seq != b.seq {
+			duplicate = true
+		}
+		return orderedComparator(a.seq, b.seq)
+	}, t.tree.owner)
+	t.tree.count++
+	if !duplicate {
+		t.keys++
+	}
+}
+
+// EraseOne removes the earliest inserted element with the given key.
+// Can return an error when such Key wasn't present.
+func (t *AVLMultiTree[KeyT, ValueT]) EraseOne(key KeyT) error {
+	c := t.first(key)
+	if !c.Valid() {
+		return &KeyError[KeyT]{Key: key, Err: ErrKeyNotFound}
+	}
+	target := c.Key()
+	if !c.Next() || t.compare(key, c.Key().key) != 0 {
+		t.keys--
+	}
+	t.tree.Erase(target)
+	return nil
+}
+
+// EraseAll removes all elements with the given key.
+// Returns the number of removed elements.
+func (t *AVLMultiTree[KeyT, ValueT]) EraseAll(key KeyT) uint {
+	removed := t.Count(key)
+	for i := uint(0); i < removed; i++ {
+		t.EraseOne(key)
+	}
+	return removed
+}
+
+// Clear removes all tree content
+func (t *AVLMultiTree[KeyT, ValueT]) Clear() {
+	t.tree.Clear()
+	t.keys = 0
+}
+
+// Enumerate calls 'Enumerator' for every Tree's element including duplicates.
+// Enumeration order can be one from ASCENDING or DESCENDING.
+// Elements with the same key are visited in the insertion order for ASCENDING and in the reverse one for DESCENDING.
+// Enumerator should return `false` for stop enumerating or `true` for continue
+func (t *AVLMultiTree[KeyT, ValueT]) Enumerate(order EnumerationOrder, f Enumerator[KeyT, ValueT]) {
+	t.tree.Enumerate(order, func(key multiKey[KeyT], value ValueT) bool {
+		return f(key.key, value)
+	})
+}
+
+// EnumerateDiapason works like Enumerate but has two additional args - left and right
+// See AVLTree.EnumerateDiapason for the details.
+func (t *AVLMultiTree[KeyT, ValueT]) EnumerateDiapason(left, right *KeyT, order EnumerationOrder, f Enumerator[KeyT, ValueT]) error {
+	if left != nil && right != nil && t.compare(*left, *right) > 0 {
+		return &RangeError[KeyT]{Left: *left, Right: *right}
+	}
+	var lower, upper *multiKey[KeyT]
+	if left != nil {
+		l, _ := bounds(*left)
+		lower = &l
+	}
+	if right != nil {
+		_, r := bounds(*right)
+		upper = &r
+	}
+	return t.tree.EnumerateDiapason(lower, upper, order, func(key multiKey[KeyT], value ValueT) bool {
+		return f(key.key, value)
+	})
+}
@@ -0,0 +1,170 @@
+package avltree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiTreeInsert(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAVLMultiTreeOrderedKey[int, string]()
+	require.True(tree.Empty())
+	require.Nil(tree.FindAll(1))
+	require.Equal(uint(0), tree.Count(1))
+
+	tree.Insert(1, "a")
+	tree.Insert(2, "b")
+	tree.Insert(1, "c")
+	tree.Insert(1, "d")
+
+	require.False(tree.Empty())
+	require.Equal(uint(4), tree.Size())
+	require.Equal(uint(2), tree.KeysCount())
+	require.True(tree.Contains(1))
+	require.False(tree.Contains(3))
+	require.Equal(uint(3), tree.Count(1))
+	require.Equal([]string{"a", "c", "d"}, tree.FindAll(1))
+	require.Equal([]string{"b"}, tree.FindAll(2))
+}
+
+func TestMultiTreeErase(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAVLMultiTreeOrderedKey[int, string]()
+	tree.Insert(1, "a")
+	tree.Insert(1, "b")
+	tree.Insert(1, "c")
+	tree.Insert(2, "d")
+
+	require.Nil(tree.EraseOne(1))
+	require.Equal([]string{"b", "c"}, tree.FindAll(1))
+	require.Equal(uint(3), tree.Size())
+
+	require.Error(tree.EraseOne(3))
+
+	require.Equal(uint(2), tree.EraseAll(1))
+	require.False(tree.Contains(1))
+	require.Equal(uint(1), tree.Size())
+	require.Equal(uint(0), tree.EraseAll(1))
+
+	require.Nil(tree.EraseOne(2))
+	require.True(tree.Empty())
+	require.Equal(uint(0), tree.KeysCount())
+
+	tree.Insert(5, "e")
+	tree.Clear()
+	require.True(tree.Empty())
+	require.False(tree.Contains(5))
+}
+
+func TestMultiTreeEnumerate(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAVLMultiTreeOrderedKey[int, int]()
+	for i := 0; i < 10; i++ {
+		tree.Insert(i%3, i)
+	}
+
+	expected := []int{0, 3, 6, 9, 1, 4, 7, 2, 5, 8}
+	result := []int{}
+	tree.Enumerate(ASCENDING, func(k int, v int) bool {
+		require.Equal(v%3, k)
+		result = append(result, v)
+		return true
+	})
+	require.Equal(expected, result)
+
+	result = result[:0]
+	tree.Enumerate(DESCENDING, func(k int, v int) bool {
+		result = append(result, v)
+		return true
+	})
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	require.Equal(expected, result)
+
+	// Interrupt inside of duplicates
+	result = result[:0]
+	tree.Enumerate(ASCENDING, func(k int, v int) bool {
+		result = append(result, v)
+		return v != 6
+	})
+	require.Equal([]int{0, 3, 6}, result)
+
+	left, right := 1, 2
+	result = result[:0]
+	err := tree.EnumerateDiapason(&left, &right, ASCENDING, func(k int, v int) bool {
+		result = append(result, v)
+		return true
+	})
+	require.Nil(err)
+	require.Equal([]int{1, 4, 7, 2, 5, 8}, result)
+}
+
+func TestMultiTreeFindAllCopy(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAVLMultiTreeOrderedKey[int, int]()
+	tree.Insert(1, 10)
+	tree.Insert(1, 11)
+	values := tree.FindAll(1)
+	values[0] = 0
+	require.Equal([]int{10, 11}, tree.FindAll(1))
+	require.Equal(uint(2), tree.Size())
+}
+
+func TestMultiTreeRandom(t *testing.T) {
+	require := require.New(t)
+
+	rnd := rand.New(rand.NewSource(1))
+	tree := NewAVLMultiTreeOrderedKey[int, int]()
+	model := map[int][]int{}
+	size := 0
+	for i := 0; i < 5000; i++ {
+		key := rnd.Intn(50)
+		switch rnd.Intn(5) {
+		case 0:
+			err := tree.EraseOne(key)
+			require.Equal(len(model[key]) == 0, err != nil)
+			if len(model[key]) != 0 {
+				model[key] = model[key][1:]
+				size--
+			}
+		case 1:
+			require.Equal(uint(len(model[key])), tree.EraseAll(key))
+			size -= len(model[key])
+			delete(model, key)
+		default:
+			tree.Insert(key, i)
+			model[key] = append(model[key], i)
+			size++
+		}
+		if len(model[key]) == 0 {
+			delete(model, key)
+		}
+
+		require.Equal(uint(size), tree.Size())
+		require.Equal(uint(len(model)), tree.KeysCount())
+		require.Equal(uint(len(model[key])), tree.Count(key))
+		require.Equal(model[key], tree.FindAll(key))
+		require.Equal(len(model[key]) != 0, tree.Contains(key))
+	}
+	require.Nil(tree.tree.Validate())
+
+	result := []int{}
+	left := 10
+	require.Nil(tree.EnumerateDiapason(&left, nil, ASCENDING, func(k int, v int) bool {
+		require.GreaterOrEqual(k, 10)
+		result = append(result, v)
+		return true
+	}))
+	expected := []int{}
+	for k := 10; k < 50; k++ {
+		expected = append(expected, model[k]...)
+	}
+	require.Equal(expected, result)
+}
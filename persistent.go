@@ -0,0 +1,184 @@
+package avltree
+
+import "golang.org/x/exp/constraints"
+
+// PersistentAVLTree is an immutable sorted associative container that contains key-value pairs with unique keys.
+// Modification methods don't change a tree but return a new version of it.
+// Versions share unchanged subtrees, so every modification copies only a logarithmic number of nodes.
+// Since a version is never changed it can be read by several goroutines without any synchronization.
+// As usual PersistentAVLTree instance creation is allowed via `NewPersistentAVLTreeOrderedKey` or `NewPersistentAVLTree`
+type PersistentAVLTree[KeyT any, ValueT any] struct {
+	root    *node[KeyT, ValueT]
+	height  int
+	compare Comparator[KeyT]
+}
+
+// NewPersistentAVLTree creates a new empty PersistentAVLTree instance with the given Comparator
+func NewPersistentAVLTree[KeyT any, ValueT any](c Comparator[KeyT]) *PersistentAVLTree[KeyT, ValueT] {
+	return &PersistentAVLTree[KeyT, ValueT]{
+		compare: c,
+	}
+}
+
+// NewPersistentAVLTreeOrderedKey creates a new empty PersistentAVLTree instance where Key type is constraints.Ordered.
+// This is actually the same as NewPersistentAVLTree but Comparator will be defined automaticaly inside the call.
+func NewPersistentAVLTreeOrderedKey[KeyT constraints.Ordered, ValueT any]() *PersistentAVLTree[KeyT, ValueT] {
+	return NewPersistentAVLTree[KeyT, ValueT](orderedComparator[KeyT])
+}
+
+func copyNode[KeyT any, ValueT any](n *node[KeyT, ValueT]) *node[KeyT, ValueT] {
+	c := *n
+	return &c
+}
+
+// persistentBalance works like avlBalance but copies nodes these are changed by rotations.
+// The *pathTop node must be already copied.
+func persistentBalance[KeyT any, ValueT any](pathTop **node[KeyT, ValueT], heights [2]int) int {
+	n := *pathTop
+	if diff := heights[1] - heights[0]; diff == 2 || diff == -2 {
+		dir := (diff + 2) >> 2
+		n.links[dir] = copyNode(n.links[dir])
+		if inner := n.links[dir].links[1-dir]; n.links[dir].balance == 1-dir {
+			n.links[dir].links[1-dir] = copyNode(inner)
+		}
+	}
+	return avlBalance(pathTop, heights)
+}
+
+func persistentInsert[KeyT any, ValueT any](n *node[KeyT, ValueT], h int, key KeyT, value ValueT, cmp Comparator[KeyT]) (*node[KeyT, ValueT], int, bool) {
+	if n == nil {
+		n = &node[KeyT, ValueT]{
+			key:     key,
+			value:   value,
+			balance: -1,
+		}
+		n.update()
+		return n, 1, true
+	}
+	cmpRes := cmp(key, n.key)
+	if cmpRes == 0 {
+		return n, h, false
+	}
+	dir := n.getDirection(cmpRes)
+	child, hchild, ok := persistentInsert(n.links[dir], n.childHeight(h, dir), key, value, cmp)
+	if !ok {
+		return n, h, false
+	}
+	var heights [2]int
+	heights[dir], heights[1-dir] = hchild, n.childHeight(h, 1-dir)
+	c := copyNode(n)
+	c.links[dir] = child
+	h = persistentBalance(&c, heights)
+	return c, h, true
+}
+
+// persistentEraseFirst removes the first node from n subtree.
+// Returns a new root with its height and the removed node.
+func persistentEraseFirst[KeyT any, ValueT any](n *node[KeyT, ValueT], h int) (*node[KeyT, ValueT], int, *node[KeyT, ValueT]) {
+	if n.links[0] == nil {
+		return n.links[1], n.childHeight(h, 1), n
+	}
+	child, hchild, first := persistentEraseFirst(n.links[0], n.childHeight(h, 0))
+	c := copyNode(n)
+	c.links[0] = child
+	h = persistentBalance(&c, [2]int{hchild, n.childHeight(h, 1)})
+	return c, h, first
+}
+
+func persistentErase[KeyT any, ValueT any](n *node[KeyT, ValueT], h int, key KeyT, cmp Comparator[KeyT]) (*node[KeyT, ValueT], int, bool) {
+	if n == nil {
+		return nil, 0, false
+	}
+	cmpRes := cmp(key, n.key)
+	if cmpRes == 0 {
+		if n.links[0] == nil {
+			return n.links[1], n.childHeight(h, 1), true
+		}
+		if n.links[1] == nil {
+			return n.links[0], n.childHeight(h, 0), true
+		}
+		right, hright, first := persistentEraseFirst(n.links[1], n.childHeight(h, 1))
+		c := copyNode(first)
+		c.links[0], c.links[1] = n.links[0], right
+		h = persistentBalance(&c, [2]int{n.childHeight(h, 0), hright})
+		return c, h, true
+	}
+	dir := n.getDirection(cmpRes)
+	child, hchild, ok := persistentErase(n.links[dir], n.childHeight(h, dir), key, cmp)
+	if !ok {
+		return n, h, false
+	}
+	var heights [2]int
+	heights[dir], heights[1-dir] = hchild, n.childHeight(h, 1-dir)
+	c := copyNode(n)
+	c.links[dir] = child
+	h = persistentBalance(&c, heights)
+	return c, h, true
+}
+
+// view returns a read-only AVLTree that shares nodes with this version.
+func (t *PersistentAVLTree[KeyT, ValueT]) view() *AVLTree[KeyT, ValueT] {
+	return &AVLTree[KeyT, ValueT]{
+		root:    t.root,
+		count:   t.root.getSize(),
+		compare: t.compare,
+	}
+}
+
+// Size returns the number of elements
+func (t *PersistentAVLTree[KeyT, ValueT]) Size() uint {
+	return t.root.getSize()
+}
+
+// Empty checks whether the container is empty
+func (t *PersistentAVLTree[KeyT, ValueT]) Empty() bool {
+	return t.root == nil
+}
+
+// Contains checks if the container contains element with the specific key
+func (t *PersistentAVLTree[KeyT, ValueT]) Contains(key KeyT) bool {
+	return t.view().lookupNode(key) != nil
+}
+
+// Find finds element with specific key
+// Returns a copy of the associated with the key value and true.
+// When key isn't present returns zero value and false.
+func (t *PersistentAVLTree[KeyT, ValueT]) Find(key KeyT) (ValueT, bool) {
+	if n := t.view().lookupNode(key); n != nil {
+		return n.value, true
+	}
+	var zero ValueT
+	return zero, false
+}
+
+// Insert returns a new version of the tree with the given key and value inserted.
+// It the given key is already present returns an error.
+func (t *PersistentAVLTree[KeyT, ValueT]) Insert(key KeyT, value ValueT) (*PersistentAVLTree[KeyT, ValueT], error) {
+	root, h, ok := persistentInsert(t.root, t.height, key, value, t.compare)
+	if !ok {
+		return nil, ErrKeyExists
+	}
+	return &PersistentAVLTree[KeyT, ValueT]{root: root, height: h, compare: t.compare}, nil
+}
+
+// Erase returns a new version of the tree without an element with the given key.
+// Can return an error when such Key wasn't present.
+func (t *PersistentAVLTree[KeyT, ValueT]) Erase(key KeyT) (*PersistentAVLTree[KeyT, ValueT], error) {
+	root, h, ok := persistentErase(t.root, t.height, key, t.compare)
+	if !ok {
+		return nil, &KeyError[KeyT]{Key: key, Err: ErrKeyNotFound}
+	}
+	return &PersistentAVLTree[KeyT, ValueT]{root: root, height: h, compare: t.compare}, nil
+}
+
+// Enumerate calls 'Enumerator' for every Tree's element.
+// See AVLTree.Enumerate for the details.
+func (t *PersistentAVLTree[KeyT, ValueT]) Enumerate(order EnumerationOrder, f Enumerator[KeyT, ValueT]) {
+	t.view().Enumerate(order, f)
+}
+
+// EnumerateDiapason works like Enumerate but has two additional args - left and right
+// See AVLTree.EnumerateDiapason for the details.
+func (t *PersistentAVLTree[KeyT, ValueT]) EnumerateDiapason(left, right *KeyT, order EnumerationOrder, f Enumerator[KeyT, ValueT]) error {
+	return t.view().EnumerateDiapason(left, right, order, f)
+}
@@ -0,0 +1,115 @@
+package avltree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentModification(t *testing.T) {
+	require := require.New(t)
+
+	empty := NewPersistentAVLTreeOrderedKey[int, string]()
+	require.True(empty.Empty())
+	require.Equal(uint(0), empty.Size())
+
+	v1, err := empty.Insert(1, "1")
+	require.Nil(err)
+	v2, err := v1.Insert(2, "2")
+	require.Nil(err)
+	_, err = v2.Insert(2, "2")
+	require.Error(err)
+
+	require.True(empty.Empty())
+	require.Equal(uint(1), v1.Size())
+	require.Equal(uint(2), v2.Size())
+	require.False(v1.Contains(2))
+	require.True(v2.Contains(2))
+
+	value, ok := v2.Find(1)
+	require.True(ok)
+	require.Equal("1", value)
+	_, ok = v2.Find(3)
+	require.False(ok)
+
+	v3, err := v2.Erase(1)
+	require.Nil(err)
+	_, err = v3.Erase(1)
+	require.Error(err)
+	require.False(v3.Contains(1))
+	require.True(v2.Contains(1))
+}
+
+func TestPersistentVersions(t *testing.T) {
+	require := require.New(t)
+
+	rnd := rand.New(rand.NewSource(1))
+	versions := []*PersistentAVLTree[int, int]{NewPersistentAVLTreeOrderedKey[int, int]()}
+	models := []map[int]int{{}}
+	for i := 0; i < 1000; i++ {
+		tree := versions[len(versions)-1]
+		model := map[int]int{}
+		for k, v := range models[len(models)-1] {
+			model[k] = v
+		}
+		key := rnd.Intn(200)
+		if rnd.Intn(3) == 0 {
+			next, err := tree.Erase(key)
+			if _, ok := model[key]; ok {
+				require.Nil(err)
+				delete(model, key)
+				tree = next
+			} else {
+				require.Error(err)
+			}
+		} else {
+			next, err := tree.Insert(key, i)
+			if _, ok := model[key]; !ok {
+				require.Nil(err)
+				model[key] = i
+				tree = next
+			} else {
+				require.Error(err)
+			}
+		}
+		view := tree.view()
+		requireValidTree(require, view)
+		require.Equal(tree.height, getHeight(tree.root))
+		versions = append(versions, tree)
+		models = append(models, model)
+	}
+
+	// Old versions must be untouched
+	for i, tree := range versions {
+		require.Equal(uint(len(models[i])), tree.Size())
+		tree.Enumerate(ASCENDING, func(k int, v int) bool {
+			require.Equal(models[i][k], v)
+			return true
+		})
+	}
+}
+
+func TestPersistentEnumerate(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewPersistentAVLTreeOrderedKey[int, int]()
+	for i := 0; i < 10; i++ {
+		tree, _ = tree.Insert(i, i)
+	}
+
+	keys := []int{}
+	tree.Enumerate(DESCENDING, func(k int, v int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	require.Equal([]int{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}, keys)
+
+	keys = keys[:0]
+	left, right := 3, 5
+	require.Nil(tree.EnumerateDiapason(&left, &right, ASCENDING, func(k int, v int) bool {
+		keys = append(keys, k)
+		return true
+	}))
+	require.Equal([]int{3, 4, 5}, keys)
+}
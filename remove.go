@@ -0,0 +1,49 @@
+package avltree
+
+// removed counts and returns the removed node content.
+func (t *AVLTree[KeyT, ValueT]) removed(n *node[KeyT, ValueT]) (KeyT, ValueT, bool) {
+	if n == nil {
+		var key KeyT
+		var value ValueT
+		return key, value, false
+	}
+	t.count--
+	return n.key, n.value, true
+}
+
+// Remove removes an element by the given key and returns its value and true.
+// Returns zero value and false when such Key wasn't present.
+// It performs a single tree descent.
+func (t *AVLTree[KeyT, ValueT]) Remove(key KeyT) (ValueT, bool) {
+	_, value, ok := t.removed(avlErase(&t.root, key, t.compare, t.owner))
+	return value, ok
+}
+
+// PopFirst removes the first tree element and returns its key, value and true.
+// The last result is false when a tree is empty.
+// It performs a single tree descent.
+func (t *AVLTree[KeyT, ValueT]) PopFirst() (KeyT, ValueT, bool) {
+	return t.removed(avlEraseLocated(&t.root, func(n *node[KeyT, ValueT]) int {
+		if n.links[0] != nil {
+			return -1
+		}
+		return 0
+	}, t.compare, t.owner))
+}
+
+// PopLast removes the last tree element and returns its key, value and true.
+// The last result is false when a tree is empty.
+// It performs a single tree descent.
+func (t *AVLTree[KeyT, ValueT]) PopLast() (KeyT, ValueT, bool) {
+	// The last node is the first one without the right child on the way down from the root.
+	// Erasing continues the descent into its left subtree, so the node is remembered
+	// and all other nodes are treated as lesser ones.
+	var last *node[KeyT, ValueT]
+	return t.removed(avlEraseLocated(&t.root, func(n *node[KeyT, ValueT]) int {
+		if n == last || (last == nil && n.links[1] == nil) {
+			last = n
+			return 0
+		}
+		return 1
+	}, t.compare, t.owner))
+}
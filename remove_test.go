@@ -0,0 +1,59 @@
+package avltree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemove(t *testing.T) {
+	require := require.New(t)
+
+	tree := createTestTree(0, 99, 1)
+	value, ok := tree.Remove(50)
+	require.True(ok)
+	require.Equal(50, value)
+	value, ok = tree.Remove(50)
+	require.False(ok)
+	require.Equal(0, value)
+	require.Equal(uint(99), tree.Size())
+	require.False(tree.Contains(50))
+}
+
+func TestPop(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAVLTreeOrderedKey[int, int]()
+	_, _, ok := tree.PopFirst()
+	require.False(ok)
+	_, _, ok = tree.PopLast()
+	require.False(ok)
+
+	rnd := rand.New(rand.NewSource(1))
+	keys := rnd.Perm(1000)
+	for _, key := range keys {
+		require.Nil(tree.Insert(key, -key))
+	}
+
+	first, last := 0, len(keys)-1
+	for !tree.Empty() {
+		var key, value int
+		if rnd.Intn(2) == 0 {
+			key, value, ok = tree.PopFirst()
+			require.Equal(first, key)
+			first++
+		} else {
+			key, value, ok = tree.PopLast()
+			require.Equal(last, key)
+			last--
+		}
+		require.True(ok)
+		require.Equal(-key, value)
+		require.Equal(uint(last-first+1), tree.Size())
+		if tree.Size()%100 == 0 {
+			requireValidTree(require, tree)
+		}
+	}
+	require.Nil(tree.root)
+}
@@ -0,0 +1,195 @@
+package avltree
+
+import "golang.org/x/exp/constraints"
+
+// SetEnumerator is a function type for AVLSet enumeration.
+// See AVLSet.Enumerate and AVLSet.EnumerateDiapason for details.
+type SetEnumerator[KeyT any] func(key KeyT) bool
+
+// AVLSet is a sorted container that contains unique keys.
+// Keys are sorted by using the comparison function `Comparator`.
+// Search, removal, and insertion operations have logarithmic complexity.
+// It shares the implementation with AVLTree but nodes haven't got any value.
+// As usual AVLSet instance creation is allowed via `NewAVLSetOrderedKey` or `NewAVLSet`
+type AVLSet[KeyT any] struct {
+	tree AVLTree[KeyT, struct{}]
+}
+
+// NewAVLSet creates a new AVLSet instance with the given Comparator
+func NewAVLSet[KeyT any](c Comparator[KeyT]) *AVLSet[KeyT] {
+	return &AVLSet[KeyT]{
+		tree: AVLTree[KeyT, struct{}]{compare: c},
+	}
+}
+
+// NewAVLSetOrderedKey creates a new AVLSet instance where Key type is constraints.Ordered.
+// This is actually the same as NewAVLSet but Comparator will be defined automaticaly inside the call.
+func NewAVLSetOrderedKey[KeyT constraints.Ordered]() *AVLSet[KeyT] {
+	return NewAVLSet(orderedComparator[KeyT])
+}
+
+// Size returns the number of elements
+func (s *AVLSet[KeyT]) Size() uint {
+	return s.tree.Size()
+}
+
+// Empty checks whether the container is empty
+func (s *AVLSet[KeyT]) Empty() bool {
+	return s.tree.Empty()
+}
+
+// Has checks if the container contains the specific key
+func (s *AVLSet[KeyT]) Has(key KeyT) bool {
+	return s.tree.Contains(key)
+}
+
+// Add inserts the given key.
+// Returns false when the key is already present.
+func (s *AVLSet[KeyT]) Add(key KeyT) bool {
+	return s.tree.Insert(key, struct{}{}) == nil
+}
+
+// Remove removes the given key.
+// Returns false when the key wasn't present.
+func (s *AVLSet[KeyT]) Remove(key KeyT) bool {
+	return s.tree.Erase(key) == nil
+}
+
+// Clear removes all set content
+func (s *AVLSet[KeyT]) Clear() {
+	s.tree.Clear()
+}
+
+func keyPtr[KeyT any, ValueT any](n *node[KeyT, ValueT]) *KeyT {
+	if n == nil {
+		return nil
+	}
+	return &n.key
+}
+
+// Min returns a pointer on the smallest key.
+// Returns nil when a set is empty.
+// Key modification isn't safe!
+func (s *AVLSet[KeyT]) Min() *KeyT {
+	return keyPtr(edgeNodeImpl(s.tree.root, ASCENDING))
+}
+
+// Max returns a pointer on the greatest key.
+// Returns nil when a set is empty.
+// Key modification isn't safe!
+func (s *AVLSet[KeyT]) Max() *KeyT {
+	return keyPtr(edgeNodeImpl(s.tree.root, DESCENDING))
+}
+
+// Floor returns a pointer on the greatest key that is lesser or equal to the given key.
+// Returns nil when no such key in the set.
+// Key modification isn't safe!
+func (s *AVLSet[KeyT]) Floor(key KeyT) *KeyT {
+	return keyPtr(s.tree.findNearestNodeImpl(key, 0))
+}
+
+// Ceiling returns a pointer on the smallest key that is greater or equal to the given key.
+// Returns nil when no such key in the set.
+// Key modification isn't safe!
+func (s *AVLSet[KeyT]) Ceiling(key KeyT) *KeyT {
+	return keyPtr(s.tree.findNearestNodeImpl(key, 1))
+}
+
+// Rank returns the number of keys these are lesser than the given key.
+func (s *AVLSet[KeyT]) Rank(key KeyT) uint {
+	return s.tree.Rank(key)
+}
+
+// At returns a pointer on the i-th smallest key. Index is zero-based.
+// Returns nil when i is out of range.
+// Key modification isn't safe!
+func (s *AVLSet[KeyT]) At(i uint) *KeyT {
+	return keyPtr(s.tree.nodeAt(i))
+}
+
+// Enumerate calls 'SetEnumerator' for every set key.
+// Enumeration order can be one from ASCENDING or DESCENDING
+// SetEnumerator should return `false` for stop enumerating or `true` for continue
+func (s *AVLSet[KeyT]) Enumerate(order EnumerationOrder, f SetEnumerator[KeyT]) {
+	s.tree.enumerateNodes(order, func(n *node[KeyT, struct{}]) bool {
+		return f(n.key)
+	})
+}
+
+// EnumerateDiapason works like Enumerate but has two additional args - left and right
+// See AVLTree.EnumerateDiapason for the details.
+func (s *AVLSet[KeyT]) EnumerateDiapason(left, right *KeyT, order EnumerationOrder, f SetEnumerator[KeyT]) error {
+	return s.tree.EnumerateDiapason(left, right, order, func(key KeyT, _ struct{}) bool {
+		return f(key)
+	})
+}
+
+// combine walks both sets in parallel and collects keys for which 'keep' returns true.
+// Collected keys are already sorted, so the result is built in the linear time.
+func (s *AVLSet[KeyT]) combine(other *AVLSet[KeyT], keep func(inS, inOther bool) bool) *AVLSet[KeyT] {
+	keys := make([]KeyT, 0, s.Size()+other.Size())
+	c1, c2 := s.tree.SeekFirst(), other.tree.SeekFirst()
+	for c1.Valid() || c2.Valid() {
+		cmpRes := 0
+		if !c1.Valid() {
+			cmpRes = 1
+		} else if !c2.Valid() {
+			cmpRes = -1
+		} else {
+			cmpRes = s.tree.compare(c1.Key(), c2.Key())
+		}
+		switch {
+		case cmpRes < 0:
+			if keep(true, false) {
+				keys = append(keys, c1.Key())
+			}
+			c1.Next()
+		case cmpRes > 0:
+			if keep(false, true) {
+				keys = append(keys, c2.Key())
+			}
+			c2.Next()
+		default:
+			if keep(true, true) {
+				keys = append(keys, c1.Key())
+			}
+			c1.Next()
+			c2.Next()
+		}
+	}
+	result := NewAVLSet(s.tree.compare)
+	result.tree.assignSorted(keys, make([]struct{}, len(keys)))
+	return result
+}
+
+// Union returns a new set that contains keys from both sets.
+// Both sets must use the same Comparator. Complexity is linear.
+func (s *AVLSet[KeyT]) Union(other *AVLSet[KeyT]) *AVLSet[KeyT] {
+	return s.combine(other, func(inS, inOther bool) bool {
+		return true
+	})
+}
+
+// Intersection returns a new set that contains keys these are present in both sets.
+// Both sets must use the same Comparator. Complexity is linear.
+func (s *AVLSet[KeyT]) Intersection(other *AVLSet[KeyT]) *AVLSet[KeyT] {
+	return s.combine(other, func(inS, inOther bool) bool {
+		return inS && inOther
+	})
+}
+
+// Difference returns a new set that contains keys from s these aren't present in other.
+// Both sets must use the same Comparator. Complexity is linear.
+func (s *AVLSet[KeyT]) Difference(other *AVLSet[KeyT]) *AVLSet[KeyT] {
+	return s.combine(other, func(inS, inOther bool) bool {
+		return inS && !inOther
+	})
+}
+
+// SymmetricDifference returns a new set that contains keys these are present only in one of sets.
+// Both sets must use the same Comparator. Complexity is linear.
+func (s *AVLSet[KeyT]) SymmetricDifference(other *AVLSet[KeyT]) *AVLSet[KeyT] {
+	return s.combine(other, func(inS, inOther bool) bool {
+		return inS != inOther
+	})
+}
@@ -0,0 +1,119 @@
+package avltree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createTestSet(start int, end int, step int) *AVLSet[int] {
+	set := NewAVLSetOrderedKey[int]()
+	for i := start; i <= end; i += step {
+		set.Add(i)
+	}
+	return set
+}
+
+func setKeys(set *AVLSet[int]) []int {
+	keys := []int{}
+	set.Enumerate(ASCENDING, func(k int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+func TestSetModification(t *testing.T) {
+	require := require.New(t)
+
+	set := NewAVLSetOrderedKey[int]()
+	require.True(set.Empty())
+	require.Nil(set.Min())
+	require.Nil(set.Max())
+
+	require.True(set.Add(10))
+	require.False(set.Add(10))
+	require.True(set.Add(5))
+	require.True(set.Has(10))
+	require.False(set.Has(7))
+	require.Equal(uint(2), set.Size())
+
+	require.True(set.Remove(10))
+	require.False(set.Remove(10))
+	require.False(set.Has(10))
+	require.Equal(uint(1), set.Size())
+
+	set.Clear()
+	require.True(set.Empty())
+}
+
+func TestSetLookup(t *testing.T) {
+	require := require.New(t)
+
+	set := createTestSet(0, 100, 10)
+
+	require.Equal(0, *set.Min())
+	require.Equal(100, *set.Max())
+
+	require.Nil(set.Floor(-1))
+	require.Nil(set.Ceiling(101))
+	for i := 0; i <= 100; i += 10 {
+		require.Equal(i, *set.Floor(i))
+		require.Equal(i, *set.Ceiling(i))
+		require.Equal(i, *set.Ceiling(i - 1))
+		require.Equal(i, *set.Floor(i + 1))
+		require.Equal(uint(i/10), set.Rank(i))
+		require.Equal(i, *set.At(uint(i / 10)))
+	}
+	require.Nil(set.At(set.Size()))
+}
+
+func TestSetEnumerate(t *testing.T) {
+	require := require.New(t)
+
+	set := createTestSet(0, 20, 5)
+	require.Equal([]int{0, 5, 10, 15, 20}, setKeys(set))
+
+	keys := []int{}
+	set.Enumerate(DESCENDING, func(k int) bool {
+		keys = append(keys, k)
+		return k != 10
+	})
+	require.Equal([]int{20, 15, 10}, keys)
+
+	keys = keys[:0]
+	left, right := 3, 16
+	require.Nil(set.EnumerateDiapason(&left, &right, ASCENDING, func(k int) bool {
+		keys = append(keys, k)
+		return true
+	}))
+	require.Equal([]int{5, 10, 15}, keys)
+	require.Error(set.EnumerateDiapason(&right, &left, ASCENDING, func(k int) bool {
+		return true
+	}))
+}
+
+func TestSetAlgebra(t *testing.T) {
+	require := require.New(t)
+
+	set1 := createTestSet(0, 10, 2)
+	set2 := createTestSet(0, 10, 3)
+
+	require.Equal([]int{0, 2, 3, 4, 6, 8, 9, 10}, setKeys(set1.Union(set2)))
+	require.Equal([]int{0, 6}, setKeys(set1.Intersection(set2)))
+	require.Equal([]int{2, 4, 8, 10}, setKeys(set1.Difference(set2)))
+	require.Equal([]int{3, 9}, setKeys(set2.Difference(set1)))
+	require.Equal([]int{2, 3, 4, 8, 9, 10}, setKeys(set1.SymmetricDifference(set2)))
+
+	empty := NewAVLSetOrderedKey[int]()
+	require.Equal(setKeys(set1), setKeys(set1.Union(empty)))
+	require.True(set1.Intersection(empty).Empty())
+	require.Equal(setKeys(set1), setKeys(set1.Difference(empty)))
+	require.True(empty.Difference(set1).Empty())
+
+	// Results are perfectly balanced trees with valid sizes
+	union := createTestSet(0, 1000, 2).Union(createTestSet(0, 1000, 3))
+	require.Equal(uint(668), union.Size())
+	require.Nil(union.tree.Validate())
+	require.LessOrEqual(avlHeight(union.tree.root), 10)
+}
@@ -0,0 +1,79 @@
+package avltree
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// buildBalanced builds a perfectly balanced tree with count nodes.
+// It calls 'next' exactly count times in the ascending order, so the caller should provide sorted elements.
+// Building stops on the first error returned by 'next'.
+// Complexity is linear. Recursion depth is logarithmic.
+func buildBalanced[KeyT any, ValueT any](count uint, next func() (KeyT, ValueT, error)) (*node[KeyT, ValueT], error) {
+	if count == 0 {
+		return nil, nil
+	}
+	rightSize := (count - 1) / 2
+	leftSize := count - 1 - rightSize
+
+	n := &node[KeyT, ValueT]{
+		balance: -1,
+	}
+	var err error
+	if n.links[0], err = buildBalanced(leftSize, next); err != nil {
+		return nil, err
+	}
+	if n.key, n.value, err = next(); err != nil {
+		return nil, err
+	}
+	if n.links[1], err = buildBalanced(rightSize, next); err != nil {
+		return nil, err
+	}
+
+	// Left subtree is never lower than the right one
+	if bits.Len(leftSize) > bits.Len(rightSize) {
+		n.balance = 0
+	}
+	n.update()
+	return n, nil
+}
+
+// assignSorted replaces the tree content by the given elements.
+// Keys must be sorted and unique, values[i] is a value for keys[i].
+func (t *AVLTree[KeyT, ValueT]) assignSorted(keys []KeyT, values []ValueT) {
+	i := 0
+	t.count = uint(len(keys))
+	t.owner = nil
+	t.root, _ = buildBalanced(t.count, func() (KeyT, ValueT, error) {
+		i++
+		return keys[i-1], values[i-1], nil
+	})
+}
+
+// checkSorted returns an error when keys aren't strictly ascending.
+func checkSorted[KeyT any](c Comparator[KeyT], keys []KeyT) error {
+	for i := 1; i < len(keys); i++ {
+		if c(keys[i-1], keys[i]) >= 0 {
+			return fmt.Errorf("AVLTree: key at %d isn't greater than the previous one", i)
+		}
+	}
+	return nil
+}
+
+// NewAVLTreeFromSorted creates a new AVLTree instance with the given Comparator and fills it by the given keys and values.
+// keys must be sorted in the ascending order and unique, values[i] is a value for keys[i].
+// Otherwise returns an error.
+// Unlike Insert calls it builds a perfectly balanced tree in the linear time.
+func NewAVLTreeFromSorted[KeyT any, ValueT any](c Comparator[KeyT], keys []KeyT, values []ValueT) (*AVLTree[KeyT, ValueT], error) {
+	if len(keys) != len(values) {
+		return nil, errors.New("AVLTree: keys and values have different length")
+	}
+	if err := checkSorted(c, keys); err != nil {
+		return nil, err
+	}
+
+	t := NewAVLTree[KeyT, ValueT](c)
+	t.assignSorted(keys, values)
+	return t, nil
+}
@@ -0,0 +1,61 @@
+package avltree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromSorted(t *testing.T) {
+	require := require.New(t)
+
+	for count := 0; count < 200; count++ {
+		keys := make([]int, count)
+		values := make([]string, count)
+		for i := range keys {
+			keys[i] = i * 2
+			values[i] = string(rune('a' + i%26))
+		}
+
+		tree, err := NewAVLTreeFromSorted(orderedComparator[int], keys, values)
+		require.Nil(err)
+		require.Equal(uint(count), tree.Size())
+		require.Equal(tree.Size(), checkSize(tree.root))
+		tree.checkHeight(func(hl int, hr int) {
+			require.LessOrEqual(abs(hl-hr), 1)
+		})
+
+		i := 0
+		tree.Enumerate(ASCENDING, func(k int, v string) bool {
+			require.Equal(keys[i], k)
+			require.Equal(values[i], v)
+			i++
+			return true
+		})
+		require.Equal(count, i)
+
+		// The tree must stay consistent after modifications
+		require.Nil(tree.Insert(-1, ""))
+		require.Nil(tree.Insert(count*2+1, ""))
+		for _, k := range keys {
+			require.Nil(tree.Erase(k))
+		}
+		tree.checkHeight(func(hl int, hr int) {
+			require.LessOrEqual(abs(hl-hr), 1)
+		})
+		require.Equal(uint(2), tree.Size())
+	}
+}
+
+func TestFromSortedErrors(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewAVLTreeFromSorted(orderedComparator[int], []int{1, 2}, []int{1})
+	require.Error(err)
+
+	_, err = NewAVLTreeFromSorted(orderedComparator[int], []int{1, 3, 2}, []int{1, 2, 3})
+	require.Error(err)
+
+	_, err = NewAVLTreeFromSorted(orderedComparator[int], []int{1, 2, 2}, []int{1, 2, 3})
+	require.Error(err)
+}
@@ -0,0 +1,191 @@
+package avltree
+
+import (
+	"io"
+	"sync"
+
+	"golang.org/x/exp/constraints"
+)
+
+// SyncAVLTree is a concurrency-safe wrapper over AVLTree guarded by sync.RWMutex.
+// Reading methods can be called concurrently, modification methods are exclusive.
+// Unlike AVLTree it never returns pointers on stored keys and values since they can't be guarded by the lock.
+// Methods return copies instead. Use Modify for an in-place value modification.
+// Note: a copy of a pointer or a slice still refers the same memory, so key and value types
+// with indirections should be treated as read-only.
+// As usual SyncAVLTree instance creation is allowed via `NewSyncAVLTreeOrderedKey` or `NewSyncAVLTree`
+type SyncAVLTree[KeyT any, ValueT any] struct {
+	mu   sync.RWMutex
+	tree *AVLTree[KeyT, ValueT]
+}
+
+// NewSyncAVLTree creates a new SyncAVLTree instance with the given Comparator
+func NewSyncAVLTree[KeyT any, ValueT any](c Comparator[KeyT]) *SyncAVLTree[KeyT, ValueT] {
+	return &SyncAVLTree[KeyT, ValueT]{
+		tree: NewAVLTree[KeyT, ValueT](c),
+	}
+}
+
+// NewSyncAVLTreeOrderedKey creates a new SyncAVLTree instance where Key type is constraints.Ordered.
+// This is actually the same as NewSyncAVLTree but Comparator will be defined automaticaly inside the call.
+func NewSyncAVLTreeOrderedKey[KeyT constraints.Ordered, ValueT any]() *SyncAVLTree[KeyT, ValueT] {
+	return NewSyncAVLTree[KeyT, ValueT](orderedComparator[KeyT])
+}
+
+// copyElement copies the node content. Nodes are looked up directly since AVLTree methods
+// these return pointers can copy nodes of a tree shared by Snapshot, what isn't allowed under the read lock.
+func copyElement[KeyT any, ValueT any](n *node[KeyT, ValueT]) (KeyT, ValueT, bool) {
+	if n == nil {
+		var k KeyT
+		var v ValueT
+		return k, v, false
+	}
+	return n.key, n.value, true
+}
+
+// Size returns the number of elements
+func (t *SyncAVLTree[KeyT, ValueT]) Size() uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Size()
+}
+
+// Empty checks whether the container is empty
+func (t *SyncAVLTree[KeyT, ValueT]) Empty() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Empty()
+}
+
+// Contains checks if the container contains element with the specific key
+func (t *SyncAVLTree[KeyT, ValueT]) Contains(key KeyT) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Contains(key)
+}
+
+// Find finds element with specific key
+// Returns a copy of the associated with the key value and true.
+// When key isn't present returns zero value and false.
+func (t *SyncAVLTree[KeyT, ValueT]) Find(key KeyT) (ValueT, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	_, value, ok := copyElement(t.tree.lookupNode(key))
+	return value, ok
+}
+
+// FindPrevElement returns a copy of key and value that is nearest to the given key and lesser then given key.
+// The last result is false when no such element in the tree.
+func (t *SyncAVLTree[KeyT, ValueT]) FindPrevElement(key KeyT) (KeyT, ValueT, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return copyElement(t.tree.findEdgeNodeImpl(key, 0))
+}
+
+// FindNextElement returns a copy of key and value that is nearest to the given key and greater then given key.
+// The last result is false when no such element in the tree.
+func (t *SyncAVLTree[KeyT, ValueT]) FindNextElement(key KeyT) (KeyT, ValueT, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return copyElement(t.tree.findEdgeNodeImpl(key, 1))
+}
+
+// First returns a copy of the first tree element.
+// The last result is false when a tree is empty.
+func (t *SyncAVLTree[KeyT, ValueT]) First() (KeyT, ValueT, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return copyElement(edgeNodeImpl(t.tree.root, ASCENDING))
+}
+
+// Last returns a copy of the last tree element.
+// The last result is false when a tree is empty.
+func (t *SyncAVLTree[KeyT, ValueT]) Last() (KeyT, ValueT, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return copyElement(edgeNodeImpl(t.tree.root, DESCENDING))
+}
+
+// Rank returns the number of elements these keys are lesser than the given key.
+func (t *SyncAVLTree[KeyT, ValueT]) Rank(key KeyT) uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Rank(key)
+}
+
+// At returns a copy of the i-th smallest element. Index is zero-based.
+// The last result is false when i is out of range.
+func (t *SyncAVLTree[KeyT, ValueT]) At(i uint) (KeyT, ValueT, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return copyElement(t.tree.nodeAt(i))
+}
+
+// Insert inserts an element with the given key and value.
+// It the given key is already present returns an error.
+func (t *SyncAVLTree[KeyT, ValueT]) Insert(key KeyT, value ValueT) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tree.Insert(key, value)
+}
+
+// Erase removes an element by the given key
+// Can return an error when such Key wasn't present.
+func (t *SyncAVLTree[KeyT, ValueT]) Erase(key KeyT) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tree.Erase(key)
+}
+
+// Modify calls 'f' with a pointer on the value associated with the given key under the write lock.
+// Returns false when the key isn't present.
+// The pointer must not be used after 'f' returns. 'f' must not call any SyncAVLTree method since it leads to a deadlock.
+func (t *SyncAVLTree[KeyT, ValueT]) Modify(key KeyT, f func(value *ValueT)) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	value := t.tree.Find(key)
+	if value == nil {
+		return false
+	}
+	f(value)
+	return true
+}
+
+// Clear removes all tree content
+func (t *SyncAVLTree[KeyT, ValueT]) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tree.Clear()
+}
+
+// Snapshot returns a copy of the tree content as a regular AVLTree.
+// The copy is taken by AVLTree.Snapshot in the constant time under the write lock.
+// Later modifications of the SyncAVLTree aren't visible via the copy and vice versa.
+func (t *SyncAVLTree[KeyT, ValueT]) Snapshot() *AVLTree[KeyT, ValueT] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tree.Snapshot()
+}
+
+// Enumerate calls 'Enumerator' for every Tree's element.
+// The enumeration goes over a Snapshot taken under the write lock in the constant time and 'f' is called without the lock.
+// So 'f' observes a consistent state and is allowed to call any SyncAVLTree method without a deadlock.
+// Since the tree is shared with the snapshot, its next modifications copy changed nodes until the enumeration ends.
+// See AVLTree.Enumerate for the details.
+func (t *SyncAVLTree[KeyT, ValueT]) Enumerate(order EnumerationOrder, f Enumerator[KeyT, ValueT]) {
+	t.Snapshot().Enumerate(order, f)
+}
+
+// EnumerateDiapason works like Enumerate but has two additional args - left and right
+// See AVLTree.EnumerateDiapason for the details.
+func (t *SyncAVLTree[KeyT, ValueT]) EnumerateDiapason(left, right *KeyT, order EnumerationOrder, f Enumerator[KeyT, ValueT]) error {
+	return t.Snapshot().EnumerateDiapason(left, right, order, f)
+}
+
+// BSTDump writes a Tree in graphviz digraph textual format under the read lock.
+// See AVLTree.BSTDump for the details.
+func (t *SyncAVLTree[KeyT, ValueT]) BSTDump(w io.Writer) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	t.tree.BSTDump(w)
+}
@@ -0,0 +1,132 @@
+package avltree
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncTreeAccess(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewSyncAVLTreeOrderedKey[int, int]()
+	require.True(tree.Empty())
+	_, _, ok := tree.First()
+	require.False(ok)
+
+	for i := 0; i <= 100; i += 10 {
+		require.Nil(tree.Insert(i, i))
+	}
+	require.Error(tree.Insert(10, 10))
+	require.Equal(uint(11), tree.Size())
+	require.True(tree.Contains(50))
+
+	value, ok := tree.Find(50)
+	require.True(ok)
+	require.Equal(50, value)
+	_, ok = tree.Find(55)
+	require.False(ok)
+
+	k, v, ok := tree.Last()
+	require.True(ok)
+	require.Equal(100, k)
+	require.Equal(100, v)
+
+	k, _, ok = tree.FindPrevElement(55)
+	require.True(ok)
+	require.Equal(50, k)
+	k, _, ok = tree.FindNextElement(55)
+	require.True(ok)
+	require.Equal(60, k)
+	_, _, ok = tree.FindNextElement(100)
+	require.False(ok)
+
+	require.Equal(uint(3), tree.Rank(30))
+	k, _, ok = tree.At(3)
+	require.True(ok)
+	require.Equal(30, k)
+
+	require.True(tree.Modify(50, func(value *int) {
+		*value = -50
+	}))
+	require.False(tree.Modify(55, func(value *int) {}))
+	value, _ = tree.Find(50)
+	require.Equal(-50, value)
+
+	snapshot := tree.Snapshot()
+	require.Nil(tree.Erase(50))
+	require.Error(tree.Erase(50))
+	require.True(snapshot.Contains(50))
+	require.Equal(uint(11), snapshot.Size())
+
+	tree.Clear()
+	require.True(tree.Empty())
+}
+
+func TestSyncTreeReentrantEnumerate(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewSyncAVLTreeOrderedKey[int, int]()
+	for i := 0; i < 10; i++ {
+		tree.Insert(i, i)
+	}
+
+	// Modification from the callback doesn't deadlock and isn't visible in the enumeration
+	visited := 0
+	tree.Enumerate(ASCENDING, func(k int, v int) bool {
+		require.Nil(tree.Erase(k))
+		require.Nil(tree.Insert(k+100, v))
+		visited++
+		return true
+	})
+	require.Equal(10, visited)
+	require.Equal(uint(10), tree.Size())
+	require.False(tree.Contains(0))
+
+	// Early stop over a snapshot doesn't copy the whole tree
+	for i := 0; i < 10000; i++ {
+		tree.Insert(i+1000, i)
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		tree.Enumerate(DESCENDING, func(k int, v int) bool {
+			return false
+		})
+	})
+	require.Less(allocs, float64(10))
+
+	left, right := 105, 103
+	require.Error(tree.EnumerateDiapason(&left, &right, ASCENDING, func(k int, v int) bool {
+		return true
+	}))
+}
+
+func TestSyncTreeConcurrency(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewSyncAVLTreeOrderedKey[int, int]()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				tree.Insert(g*1000+i, i)
+				tree.Find(g*1000 + i/2)
+				tree.Enumerate(ASCENDING, func(k int, v int) bool {
+					return k < g*1000
+				})
+				right := g*1000 + i
+				left := right - 2
+				tree.EnumerateDiapason(&left, &right, DESCENDING, func(k int, v int) bool {
+					tree.Modify(k, func(v *int) {
+						*v++
+					})
+					return true
+				})
+			}
+		}(g)
+	}
+	wg.Wait()
+	require.Equal(uint(8*500), tree.Size())
+}
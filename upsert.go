@@ -0,0 +1,62 @@
+package avltree
+
+// InsertOrAssign inserts an element with the given key and value.
+// When the key is already present its value is replaced by the given one.
+// Returns true when the value has been replaced.
+// It performs a single tree descent.
+func (t *AVLTree[KeyT, ValueT]) InsertOrAssign(key KeyT, value ValueT) (replaced bool) {
+	n, inserted := avlInsert(&t.root, key, value, nil, t.compare, t.owner)
+	if inserted {
+		t.count++
+		return false
+	}
+	t.own(n).value = value
+	return true
+}
+
+// GetOrInsert returns a pointer on the value associated with the given key.
+// When the key isn't present it inserts a new element with a value returned by 'create'.
+// 'create' is called only for an insertion before the tree is changed and must not modify the tree.
+// When 'create' panics the tree stays unchanged.
+// The second result is true when the element has been inserted.
+// It performs a single tree descent.
+// Value modification by the pointer is safe until the next Snapshot call.
+func (t *AVLTree[KeyT, ValueT]) GetOrInsert(key KeyT, create func() ValueT) (value *ValueT, inserted bool) {
+	var zero ValueT
+	n, inserted := avlInsert(&t.root, key, zero, func() (ValueT, bool) {
+		return create(), true
+	}, t.compare, t.owner)
+	if inserted {
+		t.count++
+		return &n.value, true
+	}
+	return &t.own(n).value, false
+}
+
+// Update calls 'f' with the value associated with the given key and true,
+// or with zero value and false when the key isn't present.
+// When 'f' returns keep == true the element is set to the returned value (it is inserted when it was absent).
+// Otherwise the element is removed (nothing happens when it was absent).
+// 'f' is called before the tree is changed and must not modify the tree. When 'f' panics the tree stays unchanged.
+// Inserting and updating perform a single tree descent, removing needs one more.
+func (t *AVLTree[KeyT, ValueT]) Update(key KeyT, f func(old ValueT, exists bool) (value ValueT, keep bool)) {
+	var zero ValueT
+	n, inserted := avlInsert(&t.root, key, zero, func() (ValueT, bool) {
+		return f(zero, false)
+	}, t.compare, t.owner)
+	if inserted {
+		t.count++
+		return
+	}
+	if n == nil {
+		// Absent and not kept
+		return
+	}
+	value, keep := f(n.value, true)
+	if keep {
+		t.own(n).value = value
+		return
+	}
+	avlErase(&t.root, key, t.compare, t.owner)
+	t.count--
+}
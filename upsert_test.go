@@ -0,0 +1,120 @@
+package avltree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertOrAssign(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAVLTreeOrderedKey[int, string]()
+	require.False(tree.InsertOrAssign(1, "a"))
+	require.False(tree.InsertOrAssign(2, "b"))
+	require.True(tree.InsertOrAssign(1, "c"))
+	require.Equal(uint(2), tree.Size())
+	require.Equal("c", *tree.Find(1))
+	require.Equal("b", *tree.Find(2))
+}
+
+func TestGetOrInsert(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAVLTreeOrderedKey[string, int]()
+	calls := 0
+	create := func() int {
+		calls++
+		return 10
+	}
+	for _, word := range []string{"a", "b", "a", "c", "a"} {
+		counter, _ := tree.GetOrInsert(word, create)
+		*counter++
+	}
+	require.Equal(3, calls)
+	require.Equal(uint(3), tree.Size())
+	require.Equal(13, *tree.Find("a"))
+	require.Equal(11, *tree.Find("b"))
+
+	value, inserted := tree.GetOrInsert("b", create)
+	require.False(inserted)
+	require.Equal(11, *value)
+	value, inserted = tree.GetOrInsert("d", create)
+	require.True(inserted)
+	require.Equal(10, *value)
+}
+
+func TestUpdate(t *testing.T) {
+	require := require.New(t)
+
+	tree := NewAVLTreeOrderedKey[int, int]()
+	model := map[int]int{}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 3000; i++ {
+		key := rnd.Intn(100)
+		remove := rnd.Intn(3) == 0
+		tree.Update(key, func(old int, exists bool) (int, bool) {
+			value, ok := model[key]
+			require.Equal(ok, exists)
+			require.Equal(value, old)
+			return old + 1, !remove
+		})
+		if remove {
+			delete(model, key)
+		} else {
+			model[key]++
+		}
+		require.Equal(uint(len(model)), tree.Size())
+	}
+	requireTreeContent(require, tree, model)
+}
+
+func TestUpdateAbsentNotKept(t *testing.T) {
+	require := require.New(t)
+
+	tree := createTestTree(0, 100, 2)
+	snapshot := tree.Snapshot()
+	root := tree.root
+	cursor := tree.Seek(50)
+	tree.Update(51, func(old int, exists bool) (int, bool) {
+		require.False(exists)
+		return 0, false
+	})
+	// Nothing happens: no rotations and no copies of shared nodes
+	require.Same(root, tree.root)
+	require.Same(snapshot.root, tree.root)
+	require.Equal(uint(51), tree.Size())
+	require.False(tree.Contains(51))
+	require.True(cursor.Next())
+	require.Equal(52, cursor.Key())
+	require.Nil(tree.Validate())
+}
+
+func TestUpsertPanic(t *testing.T) {
+	require := require.New(t)
+
+	tree := createTestTree(0, 100, 2)
+	root := tree.root
+	require.Panics(func() {
+		tree.GetOrInsert(51, func() int {
+			panic("create")
+		})
+	})
+	require.Panics(func() {
+		tree.Update(53, func(old int, exists bool) (int, bool) {
+			panic("absent")
+		})
+	})
+	require.Panics(func() {
+		tree.Update(54, func(old int, exists bool) (int, bool) {
+			panic("present")
+		})
+	})
+	require.Same(root, tree.root)
+	require.Equal(uint(51), tree.Size())
+	require.False(tree.Contains(51))
+	require.False(tree.Contains(53))
+	require.Equal(54, *tree.Find(54))
+	require.Nil(tree.Validate())
+}
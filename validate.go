@@ -0,0 +1,70 @@
+package avltree
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidTree is wrapped by errors returned from Validate
+var ErrInvalidTree = errors.New("AVLTree: invalid tree")
+
+func invalidNode[KeyT any, ValueT any](n *node[KeyT, ValueT], reason string) error {
+	return &KeyError[KeyT]{Key: n.key, Err: fmt.Errorf("%w: %s", ErrInvalidTree, reason)}
+}
+
+// validateNodes checks n subtree in the in-order. 'prev' is the previous node in the in-order.
+// Returns the subtree height and the first found violation.
+func (t *AVLTree[KeyT, ValueT]) validateNodes(n *node[KeyT, ValueT], prev **node[KeyT, ValueT]) (int, error) {
+	if n == nil {
+		return 0, nil
+	}
+	hl, err := t.validateNodes(n.links[0], prev)
+	if err != nil {
+		return 0, err
+	}
+	if *prev != nil && t.compare != nil && t.compare((*prev).key, n.key) >= 0 {
+		return 0, invalidNode(n, "key isn't greater than the previous one")
+	}
+	*prev = n
+	hr, err := t.validateNodes(n.links[1], prev)
+	if err != nil {
+		return 0, err
+	}
+
+	switch hr - hl {
+	case -1:
+		if n.balance != 0 {
+			return 0, invalidNode(n, "balance doesn't match subtrees heights")
+		}
+	case 0:
+		if n.balance != -1 {
+			return 0, invalidNode(n, "balance doesn't match subtrees heights")
+		}
+	case 1:
+		if n.balance != 1 {
+			return 0, invalidNode(n, "balance doesn't match subtrees heights")
+		}
+	default:
+		return 0, invalidNode(n, "subtrees heights differ more than by 1")
+	}
+	if n.size != n.links[0].getSize()+n.links[1].getSize()+1 {
+		return 0, invalidNode(n, "wrong subtree size")
+	}
+	return max(hl, hr) + 1, nil
+}
+
+// Validate checks the tree invariants: keys order under the tree Comparator, balance of every node
+// and the elements count. It is useful in tests and for a detection of keys modified via pointers.
+// Returns nil for a valid tree. Otherwise returns an error that wraps ErrInvalidTree.
+// When a violation is related to a node the error is KeyError with the first violating key in the ascending order.
+// Complexity is linear.
+func (t *AVLTree[KeyT, ValueT]) Validate() error {
+	var prev *node[KeyT, ValueT]
+	if _, err := t.validateNodes(t.root, &prev); err != nil {
+		return err
+	}
+	if size := t.root.getSize(); size != t.count {
+		return fmt.Errorf("%w: count %d doesn't match the number of nodes %d", ErrInvalidTree, t.count, size)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package avltree
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(NewAVLTreeOrderedKey[int, int]().Validate())
+
+	tree := NewAVLTreeOrderedKey[int, int]()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		key := rnd.Intn(300)
+		if rnd.Intn(3) == 0 {
+			tree.Erase(key)
+		} else {
+			tree.Insert(key, key)
+		}
+		require.Nil(tree.Validate())
+	}
+}
+
+func TestValidateViolations(t *testing.T) {
+	require := require.New(t)
+
+	var keyErr *KeyError[int]
+
+	// Key modification via a pointer
+	tree := createTestTree(0, 100, 1)
+	key, _ := tree.At(50)
+	*key = 70
+	err := tree.Validate()
+	require.True(errors.Is(err, ErrInvalidTree))
+	require.True(errors.As(err, &keyErr))
+	require.Equal(51, keyErr.Key)
+
+	// Wrong balance
+	tree = createTestTree(0, 100, 1)
+	n := tree.nodeAt(20)
+	n.balance = (n.balance+2)%3 - 1
+	err = tree.Validate()
+	require.True(errors.Is(err, ErrInvalidTree))
+	require.True(errors.As(err, &keyErr))
+	require.Equal(20, keyErr.Key)
+
+	// Unbalanced subtrees
+	tree = createTestTree(0, 2, 1)
+	tree.root.links[0].links[0] = &node[int, int]{key: -2, size: 1, balance: -1}
+	tree.root.links[0].links[0].links[1] = &node[int, int]{key: -1, size: 1, balance: -1}
+	err = tree.Validate()
+	require.True(errors.As(err, &keyErr))
+	require.Equal(-2, keyErr.Key)
+
+	// Wrong count
+	tree = createTestTree(0, 100, 1)
+	tree.count++
+	err = tree.Validate()
+	require.True(errors.Is(err, ErrInvalidTree))
+	require.False(errors.As(err, &keyErr))
+}